@@ -1,4 +1,22 @@
 package goshopee
 
+// Address is a pickup address of the shop, from GetLogisticInfo.
 type Address struct {
+	AddressID    uint64     `json:"address_id"`
+	Country      string     `json:"country"`
+	Region       string     `json:"region"`
+	State        string     `json:"state"`
+	City         string     `json:"city"`
+	District     string     `json:"district"`
+	Town         string     `json:"town"`
+	Address      string     `json:"address"`
+	Zipcode      string     `json:"zipcode"`
+	TimeSlotList []TimeSlot `json:"time_slot_list"`
+}
+
+// TimeSlot is a pickup time slot of an Address. PickupTimeID is what
+// LogisticService.Init expects to book the slot.
+type TimeSlot struct {
+	PickupTimeID string `json:"pickup_time_id"`
+	Date         int64  `json:"date"` // Start of the slot, as seconds since the epoch.
 }
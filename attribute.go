@@ -6,9 +6,9 @@ type Attribute struct {
 }
 
 type AttributeAdded struct {
-	ID          uint64 `json:"attribute_id"`
-	Name        string `json:"attribute_name"`
-	IsMandatory bool   `json:"is_mandatory"`
-	Type        string `json:"attribute_type"`
-	Value       string `json:"attribute_value"`
+	ID          uint64   `json:"attribute_id"`
+	Name        string   `json:"attribute_name"`
+	IsMandatory FlexBool `json:"is_mandatory"`
+	Type        string   `json:"attribute_type"`
+	Value       string   `json:"attribute_value"`
 }
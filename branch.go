@@ -1,5 +1,18 @@
 package goshopee
 
+// Branch is a logistics branch where the seller can drop parcels off, from
+// GetLogisticInfo.
 type Branch struct {
-	
-}
\ No newline at end of file
+	BranchID  uint64  `json:"branch_id"`
+	Name      string  `json:"branch_name"`
+	Country   string  `json:"country"`
+	Region    string  `json:"region"`
+	State     string  `json:"state"`
+	City      string  `json:"city"`
+	District  string  `json:"district"`
+	Town      string  `json:"town"`
+	Address   string  `json:"address"`
+	Zipcode   string  `json:"zipcode"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+}
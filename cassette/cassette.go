@@ -0,0 +1,302 @@
+// Package cassette records Shopee API interactions to a file and replays
+// them, so integration tests captured once against the sandbox can run
+// deterministically in CI.
+//
+//	rec, err := cassette.New("testdata/create_item.json", cassette.ModeReplay)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer func() {
+//		if err := rec.Stop(); err != nil {
+//			t.Error(err)
+//		}
+//	}()
+//	client := goshopee.NewClient(app, goshopee.WithTransport(rec))
+//
+// Signatures are never written to the cassette and the partner_id and
+// timestamp of the request bodies are scrubbed. Requests are matched on
+// method, path and body, ignoring the scrubbed fields.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode tells a Recorder whether to hit the network or the cassette.
+type Mode int
+
+const (
+	// ModeRecord sends requests to Shopee and saves the interactions when
+	// the Recorder is stopped.
+	ModeRecord Mode = iota
+	// ModeReplay answers requests from the cassette and fails the ones which
+	// were not recorded.
+	ModeReplay
+)
+
+// scrubbedValue replaces secrets in recorded bodies.
+const scrubbedValue = "[SCRUBBED]"
+
+// defaultScrubFields are the request body fields scrubbed from the cassette
+// and ignored when matching requests.
+var defaultScrubFields = []string{"partner_id", "timestamp"}
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Only the path of the URL is kept so a
+// cassette can be replayed against any base URL.
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Body   string `json:"body"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// Option configures a Recorder.
+type Option func(r *Recorder)
+
+// WithTransport sets the transport used to reach Shopee in ModeRecord,
+// defaults to http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithScrubFields scrubs more request body fields, e.g. "shopid", in
+// addition to partner_id and timestamp.
+func WithScrubFields(fields ...string) Option {
+	return func(r *Recorder) {
+		r.scrub = append(r.scrub, fields...)
+	}
+}
+
+// Recorder is an http.RoundTripper recording or replaying a cassette.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrub     []string
+
+	mu        sync.Mutex
+	cassette  Cassette
+	used      []bool
+	unmatched []string
+}
+
+// New returns a Recorder for the cassette file at path. In ModeReplay the
+// file must exist, in ModeRecord it is overwritten by Stop.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		scrub:     append([]string(nil), defaultScrubFields...),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == ModeReplay {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cassette: %s", err)
+		}
+		if err := json.Unmarshal(content, &r.cassette); err != nil {
+			return nil, fmt.Errorf("cassette: decode %s: %s", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Body:   string(r.scrubBody(body)),
+		},
+		Response: Response{
+			Status: resp.StatusCode,
+			Header: header,
+			Body:   string(respBody),
+		},
+	})
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := r.normalize(body)
+	match := -1
+	for i, in := range r.cassette.Interactions {
+		if in.Request.Method != req.Method || in.Request.Path != req.URL.Path {
+			continue
+		}
+		if r.normalize([]byte(in.Request.Body)) != key {
+			continue
+		}
+		// prefer the first unused interaction, a repeated request replays
+		// the last matching one
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		call := fmt.Sprintf("%s %s %s", req.Method, req.URL.Path, key)
+		r.unmatched = append(r.unmatched, call)
+		return nil, fmt.Errorf("cassette: no interaction recorded in %s for %s", r.path, call)
+	}
+	r.used[match] = true
+
+	rec := r.cassette.Interactions[match].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Header.Clone(),
+		Body:          ioutil.NopCloser(strings.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+// scrubBody replaces the scrubbed fields of a JSON body.
+func (r *Recorder) scrubBody(body []byte) []byte {
+	fields, err := decodeFields(body)
+	if err != nil {
+		return body
+	}
+	for _, f := range r.scrub {
+		if _, ok := fields[f]; ok {
+			fields[f] = scrubbedValue
+		}
+	}
+	scrubbed, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return scrubbed
+}
+
+// normalize returns the body with the scrubbed fields removed and the keys
+// sorted, so that equal requests compare equal.
+func (r *Recorder) normalize(body []byte) string {
+	fields, err := decodeFields(body)
+	if err != nil {
+		return string(bytes.TrimSpace(body))
+	}
+	for _, f := range r.scrub {
+		delete(fields, f)
+	}
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return string(body)
+	}
+	return string(normalized)
+}
+
+// decodeFields decodes a JSON object keeping numbers as they were sent.
+func decodeFields(body []byte) (map[string]interface{}, error) {
+	var fields map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Unmatched returns the replayed requests which had no recorded interaction.
+func (r *Recorder) Unmatched() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.unmatched...)
+}
+
+// Stop saves the cassette in ModeRecord. In ModeReplay it returns an error
+// when requests could not be matched.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ModeReplay {
+		if len(r.unmatched) > 0 {
+			return fmt.Errorf("cassette: %d unmatched requests in %s: %s", len(r.unmatched), r.path, strings.Join(r.unmatched, "; "))
+		}
+		return nil
+	}
+
+	content, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, content, 0644)
+}
@@ -0,0 +1,230 @@
+// Package chaos provides an http.RoundTripper injecting faults into Shopee
+// API calls, to test how code built on goshopee copes with latency, server
+// errors, rate limiting, error envelopes, malformed bodies and dropped
+// connections.
+//
+//	tr := chaos.New(nil, 1)
+//	tr.Add(chaos.Rule{Path: "/items/get", Probability: 0.3, Status: http.StatusServiceUnavailable})
+//	tr.Add(chaos.Rule{Path: "/orders/detail", Times: 1, Truncate: true})
+//	client := goshopee.NewClient(app, goshopee.WithTransport(tr), goshopee.WithRetry(3))
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Rule describes a fault and the calls it applies to. A rule may combine a
+// latency with one of the other faults, which are checked in the order
+// Reset, Status, ShopeeError, Body and Truncate.
+type Rule struct {
+	// Path restricts the rule to an endpoint, e.g. "/items/get". It matches
+	// the end of the request path, so the api prefix can be left out. An
+	// empty Path matches every call.
+	Path string
+
+	// Probability of applying the rule to a matching call, between 0 and 1.
+	// Zero applies it to every call.
+	Probability float64
+
+	// Times is the maximum number of calls the rule is applied to, zero
+	// means no limit.
+	Times int
+
+	// Latency delays the call, or the failure, by the given duration.
+	Latency time.Duration
+
+	// Reset fails the call as if the connection was reset by the peer.
+	Reset bool
+
+	// Status answers with that HTTP status without reaching Shopee, e.g.
+	// http.StatusServiceUnavailable or http.StatusTooManyRequests.
+	Status int
+
+	// RetryAfter is sent as Retry-After header along with Status.
+	RetryAfter int
+
+	// ShopeeError answers with a 200 OK Shopee error envelope carrying this
+	// error code and Message, e.g. "error_param".
+	ShopeeError string
+	Message     string
+
+	// Body answers with a 200 OK and this body, e.g. malformed JSON.
+	Body string
+
+	// Truncate cuts the real response body in half.
+	Truncate bool
+}
+
+// Injection records a fault applied to a call.
+type Injection struct {
+	Path string
+	Rule int
+}
+
+// Transport is an http.RoundTripper applying the fault rules to the calls
+// it forwards to its base transport. It is safe for concurrent use.
+type Transport struct {
+	base http.RoundTripper
+
+	mu         sync.Mutex
+	rand       *rand.Rand
+	rules      []Rule
+	applied    []int
+	injections []Injection
+}
+
+// New returns a Transport forwarding calls to base, http.DefaultTransport
+// when nil. The seed makes the probabilistic rules reproducible.
+func New(base http.RoundTripper, seed int64) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		base: base,
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Add appends a rule, the first matching rule wins.
+func (t *Transport) Add(rule Rule) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rules = append(t.rules, rule)
+	t.applied = append(t.applied, 0)
+}
+
+// Reset removes every rule and forgets the injections.
+func (t *Transport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rules = nil
+	t.applied = nil
+	t.injections = nil
+}
+
+// Injections returns the faults applied so far, in order.
+func (t *Transport) Injections() []Injection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Injection(nil), t.injections...)
+}
+
+// pick returns the rule to apply to a call on path, if any.
+func (t *Transport) pick(path string) (Rule, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, rule := range t.rules {
+		if rule.Path != "" && !strings.HasSuffix(path, "/"+strings.TrimLeft(rule.Path, "/")) {
+			continue
+		}
+		if rule.Times > 0 && t.applied[i] >= rule.Times {
+			continue
+		}
+		if rule.Probability > 0 && t.rand.Float64() >= rule.Probability {
+			continue
+		}
+		t.applied[i]++
+		t.injections = append(t.injections, Injection{Path: path, Rule: i})
+		return rule, true
+	}
+	return Rule{}, false
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rule, ok := t.pick(req.URL.Path)
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	if rule.Latency > 0 {
+		if err := sleep(req.Context(), rule.Latency); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case rule.Reset:
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	case rule.Status != 0:
+		header := http.Header{}
+		if rule.RetryAfter > 0 {
+			header.Set("Retry-After", strconv.Itoa(rule.RetryAfter))
+		}
+		return respond(req, rule.Status, header, http.StatusText(rule.Status)), nil
+	case rule.ShopeeError != "":
+		body, err := json.Marshal(map[string]string{
+			"error":      rule.ShopeeError,
+			"msg":        rule.Message,
+			"request_id": fmt.Sprintf("chaos-%d", time.Now().UnixNano()),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return respond(req, http.StatusOK, nil, string(body)), nil
+	case rule.Body != "":
+		return respond(req, http.StatusOK, nil, rule.Body), nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || !rule.Truncate {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	body = body[:len(body)/2]
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+func respond(req *http.Request, status int, header http.Header, body string) *http.Response {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+package goshopee
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the client what time it is. It is used everywhere a timestamp
+// is generated, such as request signing and default order time windows.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts an ordinary function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns f()
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// clockSkew keeps the offset between Shopee's clock and the local one,
+// learned from the Date header of the responses.
+type clockSkew struct {
+	offset int64 // nanoseconds, server minus local
+}
+
+func (s *clockSkew) get() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.offset))
+}
+
+func (s *clockSkew) set(d time.Duration) {
+	atomic.StoreInt64(&s.offset, int64(d))
+}
+
+// minClockSkew is the smallest offset worth compensating, the Date header
+// only has a precision of one second.
+const minClockSkew = 2 * time.Second
+
+// now returns the current time according to the configured clock, adjusted
+// by the server clock skew when skew compensation is enabled.
+func (c *Client) now() time.Time {
+	t := c.clock.Now()
+	if c.skew != nil {
+		t = t.Add(c.skew.get())
+	}
+	return t
+}
+
+// ClockSkew returns the detected offset between Shopee's clock and the
+// client's clock, zero when skew compensation is disabled.
+func (c *Client) ClockSkew() time.Duration {
+	if c.skew == nil {
+		return 0
+	}
+	return c.skew.get()
+}
+
+// observeServerTime updates the clock skew from the Date header of resp.
+func (c *Client) observeServerTime(resp *http.Response) {
+	if c.skew == nil || resp == nil {
+		return
+	}
+	date := resp.Header.Get("Date")
+	if date == "" {
+		return
+	}
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		c.log.Debugf("invalid Date header %q: %s", date, err)
+		return
+	}
+	offset := serverTime.Sub(c.clock.Now())
+	if offset > -minClockSkew && offset < minClockSkew {
+		offset = 0
+	}
+	if offset != c.skew.get() {
+		c.log.Debugf("server clock skew is %s", offset.String())
+		c.skew.set(offset)
+	}
+}
@@ -0,0 +1,145 @@
+package goshopee
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+var localTime = time.Unix(1700000000, 0).UTC()
+
+func fixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+func dateResponse(t time.Time) *http.Response {
+	return &http.Response{Header: http.Header{"Date": []string{t.Format(http.TimeFormat)}}}
+}
+
+func TestObserveServerTime(t *testing.T) {
+	cases := []struct {
+		name   string
+		offset time.Duration
+		want   time.Duration
+	}{
+		{"in sync", 0, 0},
+		{"ahead below threshold", time.Second, 0},
+		{"behind below threshold", -time.Second, 0},
+		{"ahead at threshold", 2 * time.Second, 2 * time.Second},
+		{"behind at threshold", -2 * time.Second, -2 * time.Second},
+		{"ahead", time.Hour, time.Hour},
+		{"behind", -90 * time.Second, -90 * time.Second},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewClient(App{}, WithClock(fixedClock(localTime)), WithClockSkewCompensation())
+			c.observeServerTime(dateResponse(localTime.Add(tc.offset)))
+			if got := c.ClockSkew(); got != tc.want {
+				t.Errorf("ClockSkew = %s, want %s", got, tc.want)
+			}
+			if got, want := c.now(), localTime.Add(tc.want); !got.Equal(want) {
+				t.Errorf("now = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestObserveServerTimeDisabled(t *testing.T) {
+	c := NewClient(App{}, WithClock(fixedClock(localTime)))
+	c.observeServerTime(dateResponse(localTime.Add(time.Hour)))
+	if got := c.ClockSkew(); got != 0 {
+		t.Errorf("ClockSkew = %s, want 0", got)
+	}
+	if got := c.now(); !got.Equal(localTime) {
+		t.Errorf("now = %s, want %s", got, localTime)
+	}
+}
+
+func TestObserveServerTimeInvalidDate(t *testing.T) {
+	c := NewClient(App{}, WithClock(fixedClock(localTime)), WithClockSkewCompensation())
+	c.observeServerTime(dateResponse(localTime.Add(time.Hour)))
+	c.observeServerTime(&http.Response{Header: http.Header{"Date": []string{"yesterday"}}})
+	c.observeServerTime(&http.Response{Header: http.Header{}})
+	if got := c.ClockSkew(); got != time.Hour {
+		t.Errorf("ClockSkew = %s, want the last valid skew 1h0m0s", got)
+	}
+}
+
+// skewedServer answers every request with an empty order list and a Date
+// offset from the local time, and keeps the request bodies.
+func skewedServer(offset time.Duration) (*httptest.Server, func() []map[string]interface{}) {
+	var (
+		mu     sync.Mutex
+		bodies []map[string]interface{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := ioutil.ReadAll(r.Body)
+		var params map[string]interface{}
+		json.Unmarshal(content, &params)
+		mu.Lock()
+		bodies = append(bodies, params)
+		mu.Unlock()
+		w.Header().Set("Date", localTime.Add(offset).Format(http.TimeFormat))
+		w.Write([]byte(`{"orders": [], "more": false, "request_id": "r1"}`))
+	}))
+	return srv, func() []map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]map[string]interface{}(nil), bodies...)
+	}
+}
+
+func TestClockSkewCompensation(t *testing.T) {
+	cases := []struct {
+		name       string
+		opts       []Option
+		wantOffset time.Duration
+	}{
+		{"compensated", []Option{WithClockSkewCompensation()}, -time.Hour},
+		{"not compensated", nil, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, requests := skewedServer(-time.Hour)
+			defer srv.Close()
+			opts := append([]Option{WithClock(fixedClock(localTime))}, tc.opts...)
+			c := NewClient(App{PartnerID: 1, PartnerKey: "key", APIURL: srv.URL}, opts...)
+
+			// the first call learns the skew, the next ones use it
+			if _, _, err := c.Order.ListWithPagination(7, 0, 10, nil); err != nil {
+				t.Fatalf("ListWithPagination: %v", err)
+			}
+			if _, _, err := c.Order.ListWithPagination(7, 0, 10, nil); err != nil {
+				t.Fatalf("ListWithPagination: %v", err)
+			}
+			for order := range c.Order.Iter(context.Background(), 7, nil, Cursor{}).All() {
+				t.Fatalf("Iter yielded %v, want no order", order)
+			}
+
+			reqs := requests()
+			if len(reqs) != 3 {
+				t.Fatalf("got %d requests, want 3", len(reqs))
+			}
+			if got := int64(reqs[0]["timestamp"].(float64)); got != localTime.Unix() {
+				t.Errorf("first timestamp = %d, want the local time %d", got, localTime.Unix())
+			}
+			want := localTime.Add(tc.wantOffset).Unix()
+			for i, req := range reqs[1:] {
+				if got := int64(req["timestamp"].(float64)); got != want {
+					t.Errorf("request %d timestamp = %d, want %d", i+1, got, want)
+				}
+				if got := int64(req["create_time_to"].(float64)); got != want {
+					t.Errorf("request %d create_time_to = %d, want %d", i+1, got, want)
+				}
+				if got := int64(req["create_time_from"].(float64)); got != want-15*24*3600 {
+					t.Errorf("request %d create_time_from = %d, want %d", i+1, got, want-15*24*3600)
+				}
+			}
+		})
+	}
+}
@@ -11,24 +11,24 @@ type DiscountService interface {
 }
 
 type DiscountResponse struct {
-	DiscountID uint64 `json:"discount_id"`
-	Count uint32 `json:"count"`
+	DiscountID FlexInt `json:"discount_id"`
+	Count FlexInt `json:"count"`
 	Warning string `json:"warning"`
 	RequestID string `json:"request_id"`
 	Errors []DiscountResponseError `json:"errors"`
 }
 
 type DiscountResponseError struct {
-	ItemID uint64 `json:"item_id"`
-	VariationID uint64 `json:"variation_id"`
+	ItemID FlexInt `json:"item_id"`
+	VariationID FlexInt `json:"variation_id"`
 	ErrorMsg string `json:"error_msg"`
 }
 
 type DiscountActionResponse struct {
-	DiscountID uint64 `json:"discount_id"`
+	DiscountID FlexInt `json:"discount_id"`
 	RequestID string `json:"request_id"`
-	ItemID uint64 `json:"item_id"`
-	VariationID uint64 `json:"variation_id"`
+	ItemID FlexInt `json:"item_id"`
+	VariationID FlexInt `json:"variation_id"`
 	ModifyTime int64 `json:"modify_time"`
 }
 
@@ -38,28 +38,31 @@ type Discount struct {
 	StartTime int64 `json:"start_time"`
 	EndTime int64 `json:"end_time"`
 	Status string `json:"status"`
+
+	// Raw is only set with WithRawFields.
+	Raw *RawFields `json:"-"`
 }
 
 type DiscountItem struct {
 	ID uint64 `json:"item_id"`
 	Name string `json:"item_name"`
 	PurchaseLimit uint32 `json:"purchase_limit"`
-	OriginalPrice float64 `json:"item_original_price"`
-	PromotionPrice float64 `json:"item_promotion_price"`
+	OriginalPrice Decimal `json:"item_original_price"`
+	PromotionPrice Decimal `json:"item_promotion_price"`
 	Stock uint32 `json:"stock"`
-	InflatedOriginalPrice float64 `json:"item_inflated_original_price"`
-	InflatedPromotionPrice float64 `json:"item_inflated_promotion_price"`
+	InflatedOriginalPrice Decimal `json:"item_inflated_original_price"`
+	InflatedPromotionPrice Decimal `json:"item_inflated_promotion_price"`
 	Variations []DiscountVariation `json:"variations"`
 }
 
 type DiscountVariation struct {
 	ID uint64 `json:"variation_id"`
 	Name string `json:"variation_name"`
-	OriginalPrice float64 `json:"variation_original_price"`
-	PromotionPrice float64 `json:"variation_promotion_price"`
+	OriginalPrice Decimal `json:"variation_original_price"`
+	PromotionPrice Decimal `json:"variation_promotion_price"`
 	Stock uint32 `json:"variation_stock"`
-	InflatedOriginalPrice float64 `json:"variation_inflated_original_price"`
-	InflatedPromotionPrice float64 `json:"variation_inflated_promotion_price"`
+	InflatedOriginalPrice Decimal `json:"variation_inflated_original_price"`
+	InflatedPromotionPrice Decimal `json:"variation_inflated_promotion_price"`
 }
 
 // DiscountServiceOp handles communication with the product related methods of
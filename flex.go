@@ -0,0 +1,106 @@
+package goshopee
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Shopee is not consistent about the JSON type of some fields: the same
+// field may come back as a number from one endpoint (or for one order
+// status) and as a string from another. The Flex types below decode either
+// representation and always encode in their natural JSON type.
+
+// FlexString is a string which also decodes from a JSON number or bool,
+// e.g. cancel_reason is 0 for some orders and "OUT_OF_STOCK" for others.
+// null decodes as "".
+type FlexString string
+
+// UnmarshalJSON decodes s from a JSON string, number or bool.
+func (s *FlexString) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*s = ""
+	case len(data) > 0 && data[0] == '"':
+		var v string
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*s = FlexString(v)
+	case len(data) > 0 && (data[0] == '{' || data[0] == '['):
+		return fmt.Errorf("invalid flex string %s", data)
+	default:
+		*s = FlexString(data)
+	}
+	return nil
+}
+
+// String returns s as a plain string.
+func (s FlexString) String() string {
+	return string(s)
+}
+
+// FlexInt is an int64 which also decodes from a JSON string holding an
+// integer. An empty string or null decode as 0. It types the ids and counts
+// of the responses, e.g. ItemResponse.ItemID or DiscountResponse.Count, the
+// models also sent in requests keep plain integers.
+type FlexInt int64
+
+// UnmarshalJSON decodes i from a JSON number or string.
+func (i *FlexInt) UnmarshalJSON(data []byte) error {
+	s, null, err := flexScalar(data)
+	if err != nil || null {
+		*i = 0
+		return err
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		// Large ids sometimes arrive as floats, e.g. 1.23e+09
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil || f != float64(int64(f)) {
+			return fmt.Errorf("invalid flex int %s", data)
+		}
+		n = int64(f)
+	}
+	*i = FlexInt(n)
+	return nil
+}
+
+// FlexBool is a bool which also decodes from 0/1, "0"/"1" and
+// "true"/"false". An empty string or null decode as false.
+type FlexBool bool
+
+// UnmarshalJSON decodes b from a JSON bool, number or string.
+func (b *FlexBool) UnmarshalJSON(data []byte) error {
+	s, null, err := flexScalar(data)
+	if err != nil || null {
+		*b = false
+		return err
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("invalid flex bool %s", data)
+	}
+	*b = FlexBool(v)
+	return nil
+}
+
+// flexScalar returns the text of a JSON scalar with any quotes removed. null
+// reports whether the value is null or an empty string.
+func flexScalar(data []byte) (s string, null bool, err error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", true, nil
+	}
+	s = string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if s, err = strconv.Unquote(s); err != nil {
+			return "", false, err
+		}
+		s = strings.TrimSpace(s)
+	}
+	return s, s == "", nil
+}
@@ -0,0 +1,137 @@
+package goshopee
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFlexString(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    FlexString
+		wantErr bool
+	}{
+		{in: `"OUT_OF_STOCK"`, want: "OUT_OF_STOCK"},
+		{in: `0`, want: "0"},
+		{in: `12.5`, want: "12.5"},
+		{in: `true`, want: "true"},
+		{in: `""`, want: ""},
+		{in: `null`, want: ""},
+		{in: `{}`, wantErr: true},
+		{in: `["a"]`, wantErr: true},
+	}
+	for _, tc := range cases {
+		var v struct {
+			Reason FlexString `json:"reason"`
+		}
+		err := json.Unmarshal([]byte(`{"reason":`+tc.in+`}`), &v)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("decode %s error = %v, want error %t", tc.in, err, tc.wantErr)
+			continue
+		}
+		if err == nil && v.Reason != tc.want {
+			t.Errorf("decode %s = %q, want %q", tc.in, v.Reason, tc.want)
+		}
+	}
+}
+
+func TestFlexInt(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    FlexInt
+		wantErr bool
+	}{
+		{in: `12345678901`, want: 12345678901},
+		{in: `"12345678901"`, want: 12345678901},
+		{in: `" 42 "`, want: 42},
+		{in: `-7`, want: -7},
+		{in: `1.23e+09`, want: 1230000000},
+		{in: `""`, want: 0},
+		{in: `null`, want: 0},
+		{in: `1.5`, wantErr: true},
+		{in: `"abc"`, wantErr: true},
+		{in: `true`, wantErr: true},
+		{in: `{}`, wantErr: true},
+	}
+	for _, tc := range cases {
+		var v struct {
+			ID FlexInt `json:"id"`
+		}
+		err := json.Unmarshal([]byte(`{"id":`+tc.in+`}`), &v)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("decode %s error = %v, want error %t", tc.in, err, tc.wantErr)
+			continue
+		}
+		if err == nil && v.ID != tc.want {
+			t.Errorf("decode %s = %d, want %d", tc.in, v.ID, tc.want)
+		}
+	}
+}
+
+func TestFlexBool(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    FlexBool
+		wantErr bool
+	}{
+		{in: `true`, want: true},
+		{in: `false`, want: false},
+		{in: `1`, want: true},
+		{in: `0`, want: false},
+		{in: `"1"`, want: true},
+		{in: `"true"`, want: true},
+		{in: `"false"`, want: false},
+		{in: `""`, want: false},
+		{in: `null`, want: false},
+		{in: `2`, wantErr: true},
+		{in: `"yes"`, wantErr: true},
+		{in: `[]`, wantErr: true},
+	}
+	for _, tc := range cases {
+		var v struct {
+			COD FlexBool `json:"cod"`
+		}
+		err := json.Unmarshal([]byte(`{"cod":`+tc.in+`}`), &v)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("decode %s error = %v, want error %t", tc.in, err, tc.wantErr)
+			continue
+		}
+		if err == nil && v.COD != tc.want {
+			t.Errorf("decode %s = %t, want %t", tc.in, v.COD, tc.want)
+		}
+	}
+}
+
+func TestFlexEncode(t *testing.T) {
+	v := struct {
+		Reason FlexString `json:"reason"`
+		ID     FlexInt    `json:"id"`
+		COD    FlexBool   `json:"cod"`
+	}{"0", 42, true}
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"reason":"0","id":42,"cod":true}`; string(got) != want {
+		t.Errorf("encode = %s, want %s", got, want)
+	}
+}
+
+func TestFlexResponses(t *testing.T) {
+	var discount DiscountResponse
+	body := `{"discount_id": "1000123", "count": "2", "errors": [{"item_id": 55, "variation_id": "", "error_msg": "x"}]}`
+	if err := json.Unmarshal([]byte(body), &discount); err != nil {
+		t.Fatalf("decode DiscountResponse: %v", err)
+	}
+	if discount.DiscountID != 1000123 || discount.Count != 2 || discount.Errors[0].ItemID != 55 || discount.Errors[0].VariationID != 0 {
+		t.Errorf("DiscountResponse = %+v", discount)
+	}
+
+	var items ItemsResponse
+	if err := json.Unmarshal([]byte(`{"items": [], "total": "12", "more": false}`), &items); err != nil {
+		t.Fatalf("decode ItemsResponse: %v", err)
+	}
+	if items.Total != 12 {
+		t.Errorf("ItemsResponse.Total = %d, want 12", items.Total)
+	}
+}
@@ -3,6 +3,7 @@ package goshopee
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -14,6 +15,7 @@ import (
 	"net/url"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,6 +28,12 @@ const (
 	defaultApiPathPrefix = "api/v1"
 	defaultApiVersion    = "v1"
 	defaultHttpTimeout   = 10
+
+	// defaultRateLimitBackoff is the first wait before retrying a rate limited
+	// request without Retry-After, doubled at every attempt up to
+	// maxRateLimitBackoff.
+	defaultRateLimitBackoff = time.Second
+	maxRateLimitBackoff     = 30 * time.Second
 )
 
 // App represents basic app settings such as Api key, secret, scope, and redirect url.
@@ -62,11 +70,30 @@ type Client struct {
 	apiVersion string
 
 	// max number of retries, defaults to 0 for no retries see WithRetry option
-	retries  int
-	attempts int
+	retries int
+
+	// first wait of a rate limited retry without Retry-After
+	rateLimitBackoff time.Duration
 
 	RateLimits RateLimitInfo
 
+	// clock used to generate timestamps, see WithClock
+	clock Clock
+
+	// server clock offset, nil unless WithClockSkewCompensation is set
+	skew *clockSkew
+
+	// keep the raw JSON of models, see WithRawFields
+	rawFields bool
+
+	// report unknown response fields, see WithStrictDecoding
+	strict  bool
+	onDrift SchemaDriftFunc
+
+	// check item logistics before submitting items, see
+	// WithItemLogisticsValidation
+	validateItemLogistics bool
+
 	// Services used for communicating with the API
 	Shop          ShopService
 	Item          ItemService
@@ -214,12 +241,14 @@ func NewClient(app App, opts ...Option) *Client {
 	}
 
 	c := &Client{
-		Client:     &http.Client{},
-		log:        &LeveledLogger{},
-		app:        app,
-		baseURL:    baseURL,
-		apiVersion: defaultApiVersion,
-		pathPrefix: defaultApiPathPrefix,
+		Client:           &http.Client{},
+		log:              &LeveledLogger{},
+		clock:            systemClock{},
+		app:              app,
+		baseURL:          baseURL,
+		apiVersion:       defaultApiVersion,
+		pathPrefix:       defaultApiPathPrefix,
+		rateLimitBackoff: defaultRateLimitBackoff,
 	}
 
 	c.Item = &ItemServiceOp{client: c}
@@ -244,16 +273,23 @@ func (c *Client) doGetHeaders(req *http.Request, v interface{}) (http.Header, er
 	var resp *http.Response
 	var err error
 	retries := c.retries
-	c.attempts = 0
+	attempts := 0
 	c.logRequest(req)
 
 	for {
-		c.attempts++
+		attempts++
+		if attempts > 1 && req.GetBody != nil {
+			// the previous attempt consumed the body, rewind it for the retry
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
 		resp, err = c.Client.Do(req)
 		c.logResponse(resp)
 		if err != nil {
 			return nil, err //http client errors, not api responses
 		}
+		c.observeServerTime(resp)
 
 		respErr := CheckResponseError(resp)
 		if respErr == nil {
@@ -271,8 +307,13 @@ func (c *Client) doGetHeaders(req *http.Request, v interface{}) (http.Header, er
 			// back off and retry
 
 			wait := time.Duration(rateLimitErr.RetryAfter) * time.Second
+			if wait <= 0 {
+				wait = c.backoff(attempts)
+			}
 			c.log.Debugf("rate limited waiting %s", wait.String())
-			time.Sleep(wait)
+			if err := sleepContext(req.Context(), wait); err != nil {
+				return nil, err
+			}
 			retries--
 			continue
 		}
@@ -305,44 +346,69 @@ func (c *Client) doGetHeaders(req *http.Request, v interface{}) (http.Header, er
 			return nil, err
 		}
 		if err := json.Unmarshal(content, &v); err != nil {
-			return nil, fmt.Errorf("decode resp error: %s", err)
+			return nil, ResponseDecodingError{
+				Body:    content,
+				Message: fmt.Sprintf("decode resp error: %s", err),
+				Status:  resp.StatusCode,
+			}
+		}
+		if c.rawFields || c.strict {
+			c.checkUnknownFields(req.URL.Path, content, v)
 		}
 	}
 
 	return resp.Header, nil
 }
 
+// backoff returns the wait before retrying a rate limited request which did
+// not tell when to retry.
+func (c *Client) backoff(attempts int) time.Duration {
+	wait := c.rateLimitBackoff
+	for i := 1; i < attempts && wait < maxRateLimitBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxRateLimitBackoff {
+		wait = maxRateLimitBackoff
+	}
+	return wait
+}
+
 // checkShopeeError shopee returned an error with 200 body
 // we'll handle that error in wrapSpecificError()
 // 200 %!d(string=200 OK)
 // {"msg": "package_width should bigger than 1", "request_id": "2894fe4fc158a114ea4bfbbd391820c4", "error": "error_param"}
 // {"batch_result": {"failures": [{"item_id": 100926704, "error_description": "mst shop not found", "variation_id": 5249278}, {"item_id": 100926704, "error_description": "mst shop not found", "variation_id": 5249278}, {"item_id": 100926704, "error_description": "mst shop not found", "variation_id": 5249278}, {"item_id": 100926704, "error_description": "mst shop not found", "variation_id": 5249278}], "modifications": []}, "request_id": "6e0276c51cf3944d0ce518643c9ba149"}
+// Only a non-empty top-level "error" makes the body an error envelope, the
+// word may well appear in the values of a successful response. An "error"
+// which is not a string is still an error, its JSON becomes the message.
 func (c *Client) checkShopeeError(r *http.Response, bodyBytes []byte) error {
-	if len(bodyBytes) > 0 {
-		bodyStr := string(bodyBytes)
-		if strings.Index(bodyStr, "error") > 0 && 
-			!strings.Contains(bodyStr, "errors") && 
-			!strings.Contains(bodyStr, "error_description") {
-			var shopeeError Error
-			err := json.Unmarshal(bodyBytes, &shopeeError)
-			if err != nil {
-				return ResponseDecodingError{
-					Body:    bodyBytes,
-					Message: fmt.Sprintf("decode error msg: %s", err.Error()),
-					Status:  r.StatusCode,
-				}
-			}
-			// Create the response error from the shopee error.
-			responseError := ResponseError{
-				Status:  r.StatusCode,
-				Message: shopeeError.Error + "[" + shopeeError.Message + "]",
-			}
-
-			return wrapSpecificError(r, responseError)
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(bodyBytes, &envelope); err != nil {
+		// not an envelope, bodies failing to decode are reported by the caller
+		return nil
+	}
+	raw, ok := envelope["error"]
+	if !ok || string(raw) == "null" {
+		return nil
+	}
+	var shopeeError Error
+	if err := json.Unmarshal(raw, &shopeeError.Error); err != nil {
+		shopeeError.Error = string(raw)
+	} else if shopeeError.Error == "" {
+		return nil
+	}
+	if msg, ok := envelope["msg"]; ok {
+		if err := json.Unmarshal(msg, &shopeeError.Message); err != nil {
+			shopeeError.Message = string(msg)
 		}
 	}
+	// Create the response error from the shopee error.
+	responseError := ResponseError{
+		Status:  r.StatusCode,
+		Message: shopeeError.Error + "[" + shopeeError.Message + "]",
+	}
 
-	return nil
+	return wrapSpecificError(r, responseError)
 }
 
 func (c *Client) logRequest(req *http.Request) {
@@ -398,9 +464,29 @@ func CheckResponseError(r *http.Response) error {
 		Message: r.Status,
 	}
 
+	if r.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := strconv.Atoi(r.Header.Get("Retry-After"))
+		return RateLimitError{
+			ResponseError: responseError,
+			RetryAfter:    retryAfter,
+		}
+	}
+
 	return wrapSpecificError(r, responseError)
 }
 
+// sleepContext pauses for the given duration or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
 // CreateAndDo performs a web request to Shopify with the given method (GET,
 // POST, PUT, DELETE) and relative path (e.g. "/admin/orders.json").
 // The data, options and resource arguments are optional and only relevant in
@@ -418,25 +504,59 @@ func (c *Client) CreateAndDo(method, relPath string, data, options, resource int
 	return nil
 }
 
+// Call performs a POST request against any Shopee endpoint, including the
+// ones not wrapped by a service, e.g. "/returns/get". The params are sent as
+// the request body after partner_id and timestamp are added. The request is
+// signed, retried, and its envelope errors are decoded just like the wrapped
+// services, and the response is unmarshalled into out. The caller's params
+// map is not modified. Use CallShop for shop level endpoints.
+func (c *Client) Call(ctx context.Context, path string, params map[string]interface{}, out interface{}) error {
+	data := make(map[string]interface{}, len(params)+2)
+	for k, v := range params {
+		data[k] = v
+	}
+	_, err := c.createAndDoGetHeadersWithContext(ctx, "POST", path, data, nil, out)
+	return err
+}
+
+// CallShop is Call for the shop level endpoints, it adds the shopid of shop
+// sid to params like the wrapped services do.
+func (c *Client) CallShop(ctx context.Context, sid uint64, path string, params map[string]interface{}, out interface{}) error {
+	data := make(map[string]interface{}, len(params)+1)
+	for k, v := range params {
+		data[k] = v
+	}
+	data["shopid"] = sid
+	return c.Call(ctx, path, data, out)
+}
+
 // createAndDoGetHeaders creates an executes a request while returning the response headers.
 func (c *Client) createAndDoGetHeaders(method, relPath string, data, options, resource interface{}) (http.Header, error) {
+	return c.createAndDoGetHeadersWithContext(context.Background(), method, relPath, data, options, resource)
+}
+
+// createAndDoGetHeadersWithContext is createAndDoGetHeaders bound to ctx, which
+// cancels both the http request and any retry back-off.
+func (c *Client) createAndDoGetHeadersWithContext(ctx context.Context, method, relPath string, data, options, resource interface{}) (http.Header, error) {
 	if strings.HasPrefix(relPath, "/") {
 		// make sure it's a relative path
 		relPath = strings.TrimLeft(relPath, "/")
 	}
 
 	relPath = path.Join(c.pathPrefix, relPath)
-	// TODO: if body == nil error
-	params := data.(map[string]interface{})
+	params, _ := data.(map[string]interface{})
+	if params == nil {
+		params = map[string]interface{}{}
+	}
 	params["partner_id"] = c.app.PartnerID
-	params["timestamp"] = time.Now().Unix()
+	params["timestamp"] = c.now().Unix()
 
 	req, err := c.NewRequest(method, relPath, params, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.doGetHeaders(req, resource)
+	return c.doGetHeaders(req.WithContext(ctx), resource)
 }
 
 // Get performs a GET request for the given path and saves the result in the
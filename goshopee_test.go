@@ -0,0 +1,191 @@
+package goshopee
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCheckShopeeError(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"success", `{"request_id": "r1", "item_id": 1}`, ""},
+		{"error in values", `{"request_id": "r1", "msg": "no error", "description": "error"}`, ""},
+		{"empty error", `{"request_id": "r1", "error": ""}`, ""},
+		{"null error", `{"request_id": "r1", "error": null}`, ""},
+		{"not an object", `[{"error": "error_param"}]`, ""},
+		{"envelope", `{"request_id": "r1", "error": "error_param", "msg": "invalid price"}`, "error_param[invalid price]"},
+		{"numeric error", `{"request_id": "r1", "error": 10001, "msg": "invalid shop"}`, "10001[invalid shop]"},
+		{"object error", `{"request_id": "r1", "error": {"code": "error_auth"}}`, `{"code": "error_auth"}[]`},
+		{"numeric msg", `{"request_id": "r1", "error": "error_param", "msg": 42}`, "error_param[42]"},
+	}
+	c := NewClient(App{})
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := c.checkShopeeError(&http.Response{StatusCode: http.StatusOK}, []byte(tc.body))
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("checkShopeeError = %v, want nil", err)
+			case tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr):
+				t.Errorf("checkShopeeError = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := NewClient(App{})
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 30 * time.Second},
+		{40, 30 * time.Second},
+	}
+	for _, tc := range cases {
+		if got := c.backoff(tc.attempts); got != tc.want {
+			t.Errorf("backoff(%d) = %s, want %s", tc.attempts, got, tc.want)
+		}
+	}
+}
+
+// recordServer answers with the given statuses in turn, then with body, and
+// keeps the request bodies it received.
+type recordServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	body     string
+	bodies   []map[string]interface{}
+}
+
+func newRecordServer(body string, statuses ...int) *recordServer {
+	s := &recordServer{statuses: statuses, body: body}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := ioutil.ReadAll(r.Body)
+		var params map[string]interface{}
+		json.Unmarshal(content, &params)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.bodies = append(s.bodies, params)
+		if len(s.statuses) > 0 {
+			status := s.statuses[0]
+			s.statuses = s.statuses[1:]
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(s.body))
+	}))
+	return s
+}
+
+func (s *recordServer) requests() []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]interface{}(nil), s.bodies...)
+}
+
+func TestCallShop(t *testing.T) {
+	srv := newRecordServer(`{"request_id": "r1", "count": 3}`)
+	defer srv.Close()
+	c := NewClient(App{PartnerID: 9, PartnerKey: "key", APIURL: srv.URL})
+
+	params := map[string]interface{}{"status": "NORMAL"}
+	var out struct {
+		Count int `json:"count"`
+	}
+	if err := c.CallShop(context.Background(), 7, "/returns/get", params, &out); err != nil {
+		t.Fatalf("CallShop: %v", err)
+	}
+	if out.Count != 3 {
+		t.Errorf("out.Count = %d, want 3", out.Count)
+	}
+	if want := map[string]interface{}{"status": "NORMAL"}; !reflect.DeepEqual(params, want) {
+		t.Errorf("params = %v, want them unchanged", params)
+	}
+
+	reqs := srv.requests()
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	req := reqs[0]
+	if req["shopid"] != float64(7) || req["partner_id"] != float64(9) || req["status"] != "NORMAL" {
+		t.Errorf("request = %v, want shopid 7, partner_id 9 and status NORMAL", req)
+	}
+	if _, ok := req["timestamp"]; !ok {
+		t.Errorf("request = %v, want a timestamp", req)
+	}
+}
+
+func TestCallEnvelopeError(t *testing.T) {
+	srv := newRecordServer(`{"request_id": "r1", "error": "error_param", "msg": "invalid status"}`)
+	defer srv.Close()
+	c := NewClient(App{PartnerID: 9, PartnerKey: "key", APIURL: srv.URL})
+
+	err := c.Call(context.Background(), "/returns/get", nil, &struct{}{})
+	if e, ok := err.(ResponseError); !ok || e.Message != "error_param[invalid status]" {
+		t.Errorf("Call error = %v, want the envelope error", err)
+	}
+}
+
+func TestCallRetry(t *testing.T) {
+	cases := []struct {
+		name     string
+		statuses []int
+	}{
+		{"rate limited", []int{http.StatusTooManyRequests}},
+		{"unavailable", []int{http.StatusServiceUnavailable}},
+		{"both", []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newRecordServer(`{"request_id": "r1"}`, tc.statuses...)
+			defer srv.Close()
+			c := NewClient(App{PartnerID: 9, PartnerKey: "key", APIURL: srv.URL}, WithRetry(3))
+			c.rateLimitBackoff = time.Millisecond
+
+			params := map[string]interface{}{"ordersn": "SN1"}
+			if err := c.CallShop(context.Background(), 7, "/orders/detail", params, &struct{}{}); err != nil {
+				t.Fatalf("CallShop: %v", err)
+			}
+			reqs := srv.requests()
+			if len(reqs) != len(tc.statuses)+1 {
+				t.Fatalf("got %d requests, want %d", len(reqs), len(tc.statuses)+1)
+			}
+			for i, req := range reqs {
+				if !reflect.DeepEqual(req, reqs[0]) || req["ordersn"] != "SN1" {
+					t.Errorf("request %d = %v, want the body of the first attempt %v", i, req, reqs[0])
+				}
+			}
+		})
+	}
+}
+
+func TestCallRetryExhausted(t *testing.T) {
+	srv := newRecordServer(`{"request_id": "r1"}`, http.StatusTooManyRequests, http.StatusTooManyRequests)
+	defer srv.Close()
+	c := NewClient(App{PartnerID: 9, PartnerKey: "key", APIURL: srv.URL}, WithRetry(2))
+	c.rateLimitBackoff = time.Millisecond
+
+	err := c.Call(context.Background(), "/shop/get", nil, &struct{}{})
+	if _, ok := err.(RateLimitError); !ok {
+		t.Errorf("Call error = %v, want a RateLimitError", err)
+	}
+	if n := len(srv.requests()); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
@@ -0,0 +1,183 @@
+package goshopeetest
+
+import (
+	goshopee "github.com/passwind/go-shopee"
+)
+
+// discountState is a discount and its items.
+type discountState struct {
+	discount goshopee.Discount
+	items    []goshopee.DiscountItem
+}
+
+// Discount returns the current state of a discount and its items.
+func (s *Server) Discount(sid, discountID uint64) (goshopee.Discount, []goshopee.DiscountItem, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.discounts[sid][discountID]
+	if !ok {
+		return goshopee.Discount{}, nil, false
+	}
+	return d.discount, append([]goshopee.DiscountItem(nil), d.items...), true
+}
+
+func (s *Server) discount(c *call, discountID uint64) (*discountState, error) {
+	d, ok := s.discounts[c.ShopID][discountID]
+	if !ok {
+		return nil, errNotFound("discount %d not found", discountID)
+	}
+	return d, nil
+}
+
+// upsertItems adds or replaces the items of a discount, reporting the
+// items which do not exist in the shop.
+func (s *Server) upsertItems(c *call, d *discountState, items []goshopee.DiscountItem) (uint32, []goshopee.DiscountResponseError) {
+	var count uint32
+	var errs []goshopee.DiscountResponseError
+	for _, item := range items {
+		if _, err := s.item(c, item.ID); err != nil {
+			errs = append(errs, goshopee.DiscountResponseError{ItemID: goshopee.FlexInt(item.ID), ErrorMsg: err.(*apiError).Message})
+			continue
+		}
+		replaced := false
+		for i := range d.items {
+			if d.items[i].ID == item.ID {
+				d.items[i] = item
+				replaced = true
+			}
+		}
+		if !replaced {
+			d.items = append(d.items, item)
+		}
+		count++
+	}
+	return count, errs
+}
+
+type discountRequest struct {
+	DiscountID  uint64                  `json:"discount_id"`
+	Name        string                  `json:"discount_name"`
+	StartTime   int64                   `json:"start_time"`
+	EndTime     int64                   `json:"end_time"`
+	ItemID      uint64                  `json:"item_id"`
+	VariationID uint64                  `json:"variation_id"`
+	Items       []goshopee.DiscountItem `json:"items"`
+}
+
+func (s *Server) registerDiscount() {
+	s.handle("/discount/add", func(c *call) (interface{}, error) {
+		var req discountRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		if req.Name == "" {
+			return nil, errParam("discount_name is required")
+		}
+		if req.EndTime <= req.StartTime {
+			return nil, errParam("end_time should be later than start_time")
+		}
+		d := &discountState{discount: goshopee.Discount{
+			ID:        s.newID(),
+			Name:      req.Name,
+			StartTime: req.StartTime,
+			EndTime:   req.EndTime,
+			Status:    "upcoming",
+		}}
+		count, errs := s.upsertItems(c, d, req.Items)
+		if s.discounts[c.ShopID] == nil {
+			s.discounts[c.ShopID] = map[uint64]*discountState{}
+		}
+		s.discounts[c.ShopID][d.discount.ID] = d
+		return goshopee.DiscountResponse{DiscountID: goshopee.FlexInt(d.discount.ID), Count: goshopee.FlexInt(count), Errors: errs, RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/discount/delete", func(c *call) (interface{}, error) {
+		var req discountRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		if _, err := s.discount(c, req.DiscountID); err != nil {
+			return nil, err
+		}
+		delete(s.discounts[c.ShopID], req.DiscountID)
+		return goshopee.DiscountActionResponse{DiscountID: goshopee.FlexInt(req.DiscountID), ModifyTime: s.now(), RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/discount/update", func(c *call) (interface{}, error) {
+		var req discountRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		d, err := s.discount(c, req.DiscountID)
+		if err != nil {
+			return nil, err
+		}
+		if req.Name != "" {
+			d.discount.Name = req.Name
+		}
+		if req.StartTime > 0 {
+			d.discount.StartTime = req.StartTime
+		}
+		if req.EndTime > 0 {
+			d.discount.EndTime = req.EndTime
+		}
+		return goshopee.DiscountActionResponse{DiscountID: goshopee.FlexInt(req.DiscountID), ModifyTime: s.now(), RequestID: newRequestID()}, nil
+	})
+
+	upsert := func(c *call) (interface{}, error) {
+		var req discountRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		d, err := s.discount(c, req.DiscountID)
+		if err != nil {
+			return nil, err
+		}
+		count, errs := s.upsertItems(c, d, req.Items)
+		return goshopee.DiscountResponse{DiscountID: goshopee.FlexInt(req.DiscountID), Count: goshopee.FlexInt(count), Errors: errs, RequestID: newRequestID()}, nil
+	}
+	s.handle("/discount/items/add", upsert)
+	s.handle("/discount/items/update", upsert)
+
+	s.handle("/discount/item/delete", func(c *call) (interface{}, error) {
+		var req discountRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		d, err := s.discount(c, req.DiscountID)
+		if err != nil {
+			return nil, err
+		}
+		items := d.items[:0]
+		found := false
+		for _, item := range d.items {
+			if item.ID != req.ItemID {
+				items = append(items, item)
+				continue
+			}
+			found = true
+			if req.VariationID == 0 {
+				continue
+			}
+			variations := item.Variations[:0]
+			for _, v := range item.Variations {
+				if v.ID != req.VariationID {
+					variations = append(variations, v)
+				}
+			}
+			item.Variations = variations
+			items = append(items, item)
+		}
+		if !found {
+			return nil, errNotFound("item %d not in discount %d", req.ItemID, req.DiscountID)
+		}
+		d.items = items
+		return goshopee.DiscountActionResponse{
+			DiscountID:  goshopee.FlexInt(req.DiscountID),
+			ItemID:      goshopee.FlexInt(req.ItemID),
+			VariationID: goshopee.FlexInt(req.VariationID),
+			ModifyTime:  s.now(),
+			RequestID:   newRequestID(),
+		}, nil
+	})
+}
@@ -0,0 +1,622 @@
+package goshopeetest
+
+import (
+	"sort"
+
+	goshopee "github.com/passwind/go-shopee"
+)
+
+// itemState is an item and its 2-tier variation definition.
+type itemState struct {
+	item  goshopee.Item
+	tiers []goshopee.TierVariation
+}
+
+// AddItem seeds an item of shop sid. A new item ID is assigned when
+// item.ItemID is zero, the item as stored is returned.
+func (s *Server) AddItem(sid uint64, item goshopee.Item) goshopee.Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addItem(sid, item).item
+}
+
+// Item returns the current state of an item.
+func (s *Server) Item(sid, itemID uint64) (goshopee.Item, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it, ok := s.items[sid][itemID]
+	if !ok {
+		return goshopee.Item{}, false
+	}
+	return it.item, true
+}
+
+// SetCategories seeds the categories returned for shop sid.
+func (s *Server) SetCategories(sid uint64, categories []goshopee.ItemCategory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.categories[sid] = categories
+}
+
+// SetAttributes seeds the attributes returned for category cid.
+func (s *Server) SetAttributes(cid uint64, attributes []goshopee.ItemAttribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attributes[cid] = attributes
+}
+
+func (s *Server) addItem(sid uint64, item goshopee.Item) *itemState {
+	if item.ItemID == 0 {
+		item.ItemID = s.newID()
+	}
+	item.ShopID = sid
+	if item.Status == "" {
+		item.Status = "NORMAL"
+	}
+	now := uint32(s.now())
+	if item.CreateTime == 0 {
+		item.CreateTime = now
+	}
+	if item.UpdateTime == 0 {
+		item.UpdateTime = item.CreateTime
+	}
+	for i := range item.Variations {
+		if item.Variations[i].ID == 0 {
+			item.Variations[i].ID = s.newID()
+		}
+		item.Variations[i].ItemID = item.ItemID
+	}
+	item.HasVariation = len(item.Variations) > 0
+	if s.items[sid] == nil {
+		s.items[sid] = map[uint64]*itemState{}
+	}
+	it := &itemState{item: item}
+	s.items[sid][item.ItemID] = it
+	return it
+}
+
+func (s *Server) item(c *call, itemID uint64) (*itemState, error) {
+	it, ok := s.items[c.ShopID][itemID]
+	if !ok || it.item.Status == "DELETED" {
+		return nil, errNotFound("item %d not found", itemID)
+	}
+	return it, nil
+}
+
+func (it *itemState) variation(variationID uint64) (*goshopee.Variation, error) {
+	for i := range it.item.Variations {
+		if it.item.Variations[i].ID == variationID {
+			return &it.item.Variations[i], nil
+		}
+	}
+	return nil, errNotFound("variation %d not found", variationID)
+}
+
+func (s *Server) touch(it *itemState) {
+	now := uint32(s.now())
+	it.item.UpdateTime = now
+	it.item.HasVariation = len(it.item.Variations) > 0
+	it.item.Is2tierItem = len(it.tiers) > 0
+	for i := range it.item.Variations {
+		it.item.Variations[i].ModifiedTime = now
+	}
+}
+
+type itemRequest struct {
+	ItemID      uint64           `json:"item_id"`
+	VariationID uint64           `json:"variation_id"`
+	Price       goshopee.Decimal `json:"price"`
+	Stock       uint32           `json:"stock"`
+}
+
+func (s *Server) registerItem() {
+	s.handle("/items/get", s.listItems)
+
+	s.handle("/item/get", func(c *call) (interface{}, error) {
+		var req itemRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		item := it.item
+		return goshopee.ItemDetailResponse{ItemID: goshopee.FlexInt(item.ItemID), Item: &item}, nil
+	})
+
+	s.handle("/item/add", func(c *call) (interface{}, error) {
+		var req goshopee.ItemOper
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		if req.Name == "" {
+			return nil, errParam("name is required")
+		}
+		if req.CategoryID == 0 {
+			return nil, errParam("category_id is required")
+		}
+		if err := s.checkItemLogistics(c.ShopID, req.Logistics); err != nil {
+			return nil, err
+		}
+		item := goshopee.Item{ItemBase: req.ItemBase}
+		item.ItemID = 0
+		for _, img := range req.Images {
+			item.Images = append(item.Images, img.URL)
+		}
+		it := s.addItem(c.ShopID, item)
+		created := it.item
+		return goshopee.ItemOperResponse{ItemID: goshopee.FlexInt(created.ItemID), Item: &created}, nil
+	})
+
+	s.handle("/item/update", func(c *call) (interface{}, error) {
+		var req itemRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		base := it.item.ItemBase
+		if err := c.decode(&base); err != nil {
+			return nil, err
+		}
+		base.ItemID, base.ShopID = it.item.ItemID, it.item.ShopID
+		base.Variations = it.item.Variations
+		var update struct {
+			Logistics []goshopee.Logistic `json:"logistics"`
+		}
+		if err := c.decode(&update); err != nil {
+			return nil, err
+		}
+		if update.Logistics != nil {
+			if err := s.checkItemLogistics(c.ShopID, update.Logistics); err != nil {
+				return nil, err
+			}
+		}
+		it.item.ItemBase = base
+		s.touch(it)
+		item := it.item
+		return goshopee.ItemOperResponse{ItemID: goshopee.FlexInt(item.ItemID), Item: &item}, nil
+	})
+
+	s.handle("/item/delete", func(c *call) (interface{}, error) {
+		var req itemRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		it.item.Status = "DELETED"
+		s.touch(it)
+		return goshopee.ItemDeleteResponse{ItemID: goshopee.FlexInt(req.ItemID), RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/items/update_price", func(c *call) (interface{}, error) {
+		var req itemRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if req.Price.Sign() <= 0 {
+			return nil, errParam("price should be bigger than 0")
+		}
+		it.item.Price = req.Price
+		s.touch(it)
+		return goshopee.ItemPriceOperResponse{
+			Item: &goshopee.ItemPriceOper{
+				ID:           goshopee.FlexInt(req.ItemID),
+				ModifiedTime: it.item.UpdateTime,
+				Price:        req.Price,
+			},
+			RequestID: newRequestID(),
+		}, nil
+	})
+
+	s.handle("/items/update_stock", func(c *call) (interface{}, error) {
+		var req itemRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		it.item.Stock = req.Stock
+		s.touch(it)
+		return goshopee.ItemStockOperResponse{
+			Item: &goshopee.ItemStockOper{
+				ID:           goshopee.FlexInt(req.ItemID),
+				ModifiedTime: it.item.UpdateTime,
+				Stock:        goshopee.FlexInt(req.Stock),
+			},
+			RequestID: newRequestID(),
+		}, nil
+	})
+
+	s.handle("/items/unlist", func(c *call) (interface{}, error) {
+		var req struct {
+			Items []goshopee.UnlistItemSuccess `json:"items"`
+		}
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		resp := goshopee.UnlistResponse{RequestID: newRequestID()}
+		for _, u := range req.Items {
+			it, err := s.item(c, uint64(u.ItemID))
+			if err != nil {
+				resp.Failed = append(resp.Failed, goshopee.UnlistItemFailed{ItemID: u.ItemID, ErrorDescription: err.(*apiError).Message})
+				continue
+			}
+			if u.Unlist {
+				it.item.Status = "UNLIST"
+			} else {
+				it.item.Status = "NORMAL"
+			}
+			s.touch(it)
+			resp.Success = append(resp.Success, u)
+		}
+		return resp, nil
+	})
+
+	s.handle("/item/add_variations", func(c *call) (interface{}, error) {
+		var req goshopee.AddVariationsRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		var added []goshopee.Variation
+		for _, v := range req.Variations {
+			v.ID = s.newID()
+			v.ItemID = req.ItemID
+			if v.Status == "" {
+				v.Status = "MODEL_NORMAL"
+			}
+			it.item.Variations = append(it.item.Variations, v)
+			added = append(added, v)
+		}
+		s.touch(it)
+		return goshopee.AddVariationsReponse{
+			ItemID:       goshopee.FlexInt(req.ItemID),
+			ModifiedTime: it.item.UpdateTime,
+			Variations:   added,
+			RequestID:    newRequestID(),
+		}, nil
+	})
+
+	s.handle("/item/delete_variation", func(c *call) (interface{}, error) {
+		var req itemRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := it.variation(req.VariationID); err != nil {
+			return nil, err
+		}
+		variations := it.item.Variations[:0]
+		for _, v := range it.item.Variations {
+			if v.ID != req.VariationID {
+				variations = append(variations, v)
+			}
+		}
+		it.item.Variations = variations
+		s.touch(it)
+		return goshopee.DeleteVariationResponse{
+			ItemID:       goshopee.FlexInt(req.ItemID),
+			VariationID:  goshopee.FlexInt(req.VariationID),
+			ModifiedTime: it.item.UpdateTime,
+			RequestID:    newRequestID(),
+		}, nil
+	})
+
+	s.handle("/items/update_variation_price", func(c *call) (interface{}, error) {
+		var req itemRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		v, err := it.variation(req.VariationID)
+		if err != nil {
+			return nil, err
+		}
+		v.Price = req.Price
+		s.touch(it)
+		return goshopee.UpdateVariationPriceResponse{Variation: *v, RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/items/update_variation_stock", func(c *call) (interface{}, error) {
+		var req itemRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		v, err := it.variation(req.VariationID)
+		if err != nil {
+			return nil, err
+		}
+		v.Stock = req.Stock
+		s.touch(it)
+		return goshopee.UpdateVariationStockResponse{Variation: *v, RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/items/update/vars_price", func(c *call) (interface{}, error) {
+		var req struct {
+			Variations []goshopee.VariationPriceRequest `json:"variations"`
+		}
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		resp := goshopee.VariationPriceResponse{RequestID: newRequestID()}
+		for _, p := range req.Variations {
+			v, err := s.variationOf(c, p.ItemID, p.VariationID)
+			if err != nil {
+				resp.Result.Failures = append(resp.Result.Failures, goshopee.VariationPriceResponseBatchResultFailure{
+					ItemID:           goshopee.FlexInt(p.ItemID),
+					VariationID:      goshopee.FlexInt(p.VariationID),
+					ErrorDiscription: err.(*apiError).Message,
+				})
+				continue
+			}
+			v.Price = p.Price
+			resp.Result.Modifications = append(resp.Result.Modifications, goshopee.VariationPriceResponseBatchResultModification{
+				ItemID:      goshopee.FlexInt(p.ItemID),
+				VariationID: goshopee.FlexInt(p.VariationID),
+				ItemPrice:   p.Price,
+			})
+		}
+		return resp, nil
+	})
+
+	s.registerTierVariation()
+
+	s.handle("/item/categories/get", func(c *call) (interface{}, error) {
+		return goshopee.ItemCategoriesResponse{Categories: s.categories[c.ShopID], RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/item/attributes/get", func(c *call) (interface{}, error) {
+		var req struct {
+			CategoryID uint64 `json:"category_id"`
+		}
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		return goshopee.ItemAttributesResponse{Attributes: s.attributes[req.CategoryID], RequestID: newRequestID()}, nil
+	})
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) variationOf(c *call, itemID, variationID uint64) (*goshopee.Variation, error) {
+	it, err := s.item(c, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return it.variation(variationID)
+}
+
+// listItems serves /items/get https://open.shopee.com/documents?module=2&type=1&id=375
+func (s *Server) listItems(c *call) (interface{}, error) {
+	var req struct {
+		Offset          uint32   `json:"pagination_offset"`
+		Limit           uint32   `json:"pagination_entries_per_page"`
+		UpdateTimeFrom  uint32   `json:"update_time_from"`
+		UpdateTimeTo    uint32   `json:"update_time_to"`
+		ItemStatus      []string `json:"item_status"`
+		NeedDeletedItem bool     `json:"need_deleted_item"`
+	}
+	if err := c.decode(&req); err != nil {
+		return nil, err
+	}
+	if req.Limit == 0 || req.Limit > 100 {
+		return nil, errParam("pagination_entries_per_page should be between 1 and 100")
+	}
+
+	var items []goshopee.Item
+	for _, it := range s.items[c.ShopID] {
+		if it.item.Status == "DELETED" && !req.NeedDeletedItem {
+			continue
+		}
+		if req.UpdateTimeFrom > 0 && it.item.UpdateTime < req.UpdateTimeFrom {
+			continue
+		}
+		if req.UpdateTimeTo > 0 && it.item.UpdateTime > req.UpdateTimeTo {
+			continue
+		}
+		if len(req.ItemStatus) > 0 && !contains(req.ItemStatus, it.item.Status) {
+			continue
+		}
+		items = append(items, it.item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ItemID < items[j].ItemID })
+
+	resp := goshopee.ItemsResponse{Total: goshopee.FlexInt(len(items)), RequestID: newRequestID()}
+	if int(req.Offset) < len(items) {
+		end := int(req.Offset + req.Limit)
+		if end > len(items) {
+			end = len(items)
+		}
+		resp.Items = items[req.Offset:end]
+		resp.More = end < len(items)
+	}
+	return resp, nil
+}
+
+func (s *Server) registerTierVariation() {
+	type tierRequest struct {
+		ItemID        uint64                          `json:"item_id"`
+		TierVariation []goshopee.TierVariation        `json:"tier_variation"`
+		Variation     []goshopee.TierVariationOperDef `json:"variation"`
+	}
+
+	addVariations := func(it *itemState, defs []goshopee.TierVariationOperDef) []goshopee.Variation {
+		var added []goshopee.Variation
+		for _, def := range defs {
+			v := goshopee.Variation{
+				ID:        s.newID(),
+				ItemID:    it.item.ItemID,
+				Stock:     def.Stock,
+				Price:     def.Price,
+				Status:    "MODEL_NORMAL",
+				TierIndex: def.TierIndex,
+			}
+			it.item.Variations = append(it.item.Variations, v)
+			added = append(added, goshopee.Variation{ID: v.ID, TierIndex: v.TierIndex})
+		}
+		return added
+	}
+
+	s.handle("/item/tier_var/init", func(c *call) (interface{}, error) {
+		var req tierRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if len(req.TierVariation) == 0 || len(req.TierVariation) > 2 {
+			return nil, errParam("tier_variation should have 1 or 2 tiers")
+		}
+		it.tiers = req.TierVariation
+		it.item.Variations = nil
+		added := addVariations(it, req.Variation)
+		s.touch(it)
+		return goshopee.TierVariationOperResponse{ItemID: goshopee.FlexInt(req.ItemID), VariationIDList: added, RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/item/tier_var/add", func(c *call) (interface{}, error) {
+		var req tierRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if len(it.tiers) == 0 {
+			return nil, errParam("item %d has no tier variation", req.ItemID)
+		}
+		added := addVariations(it, req.Variation)
+		s.touch(it)
+		return goshopee.TierVariationOperResponse{ItemID: goshopee.FlexInt(req.ItemID), VariationIDList: added, RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/item/tier_var/get", func(c *call) (interface{}, error) {
+		var req tierRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		var ids []goshopee.Variation
+		for _, v := range it.item.Variations {
+			ids = append(ids, goshopee.Variation{ID: v.ID, TierIndex: v.TierIndex})
+		}
+		return goshopee.TierVariationOperResponse{
+			ItemID:          goshopee.FlexInt(req.ItemID),
+			TierVariation:   it.tiers,
+			VariationIDList: ids,
+			RequestID:       newRequestID(),
+		}, nil
+	})
+
+	s.handle("/item/tier_var/update_list", func(c *call) (interface{}, error) {
+		var req tierRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		it.tiers = req.TierVariation
+		s.touch(it)
+		return goshopee.ItemResponse{ItemID: goshopee.FlexInt(req.ItemID), RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/item/tier_var/update", func(c *call) (interface{}, error) {
+		var req struct {
+			ItemID    uint64                               `json:"item_id"`
+			Variation []goshopee.TierVariationIndexOperDef `json:"variation"`
+		}
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		it, err := s.item(c, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		for _, def := range req.Variation {
+			v, err := it.variation(def.ID)
+			if err != nil {
+				return nil, err
+			}
+			v.TierIndex = def.TierIndex
+		}
+		s.touch(it)
+		return goshopee.ItemResponse{ItemID: goshopee.FlexInt(req.ItemID), RequestID: newRequestID()}, nil
+	})
+}
+
+// checkItemLogistics rejects logistics naming channels not seeded with
+// AddLogistic, enabling channels disabled for the shop, or enabling none, as
+// Shopee does. Fee, size and weight rules are left to the caller, see
+// goshopee.ValidateItemLogistics. Shops without channels accept any
+// logistics.
+func (s *Server) checkItemLogistics(sid uint64, logistics []goshopee.Logistic) error {
+	channels := s.logistics[sid]
+	if len(channels) == 0 {
+		return nil
+	}
+	enabled := 0
+	for _, l := range logistics {
+		var channel *goshopee.Logistic
+		for i := range channels {
+			if channels[i].ID == l.ID {
+				channel = &channels[i]
+				break
+			}
+		}
+		if channel == nil {
+			return errParam("logistic_id %d not found", l.ID)
+		}
+		if !l.Enabled {
+			continue
+		}
+		if !channel.Enabled {
+			return errParam("logistic_id %d is not enabled for the shop", l.ID)
+		}
+		enabled++
+	}
+	if enabled == 0 {
+		return errParam("at least one logistics channel should be enabled")
+	}
+	return nil
+}
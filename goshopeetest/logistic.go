@@ -0,0 +1,323 @@
+package goshopeetest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	goshopee "github.com/passwind/go-shopee"
+)
+
+// AddLogistic seeds a logistics channel of shop sid.
+func (s *Server) AddLogistic(sid uint64, logistic goshopee.Logistic) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logistics[sid] = append(s.logistics[sid], logistic)
+}
+
+// SetInitParameters seeds the parameters GetParameterForInit returns for an
+// order, e.g. {"pickup": {"address_id", "pickup_time_id"}}; a method
+// without an entry is not available. By default an order only supports
+// pickup and requires a pickup address and time slot.
+func (s *Server) SetInitParameters(sid uint64, ordersn string, params map[string][]string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[sid][ordersn]
+	if !ok {
+		return false
+	}
+	o.initParams = params
+	return true
+}
+
+// SetLogisticInfo seeds the pickup addresses and dropoff branches
+// GetLogisticInfo returns for an order.
+func (s *Server) SetLogisticInfo(sid uint64, ordersn string, info goshopee.GetLogisticInfoResponse) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[sid][ordersn]
+	if !ok {
+		return false
+	}
+	o.logisticInfo = &info
+	return true
+}
+
+// defaultLogisticInfo is what GetLogisticInfo returns for an order without
+// SetLogisticInfo: one pickup address with a slot on each of the next two
+// days.
+func (s *Server) defaultLogisticInfo() goshopee.GetLogisticInfoResponse {
+	day := time.Unix(s.now(), 0).Truncate(24 * time.Hour)
+	return goshopee.GetLogisticInfoResponse{
+		Pickup: goshopee.GetLogisticInfoResponsePickup{
+			AddressList: []goshopee.Address{{
+				AddressID: 1,
+				Country:   "SG",
+				City:      "Singapore",
+				Address:   "1 Fake Street",
+				Zipcode:   "000001",
+				TimeSlotList: []goshopee.TimeSlot{
+					{PickupTimeID: strconv.FormatInt(day.Add(24*time.Hour).Unix(), 10), Date: day.Add(24 * time.Hour).Unix()},
+					{PickupTimeID: strconv.FormatInt(day.Add(48*time.Hour).Unix(), 10), Date: day.Add(48 * time.Hour).Unix()},
+				},
+			}},
+		},
+	}
+}
+
+func (o *orderState) parametersForInit() map[string][]string {
+	if o.initParams != nil {
+		return o.initParams
+	}
+	return map[string][]string{
+		"pickup": {"address_id", "pickup_time_id"},
+	}
+}
+
+type logisticRequest struct {
+	OrderSN string `json:"ordersn"`
+}
+
+func (s *Server) registerLogistic() {
+	s.registerAirwayBill()
+	s.registerTracking()
+
+	s.handle("/logistics/channel/get", func(c *call) (interface{}, error) {
+		return goshopee.ListReponse{Logistics: s.logistics[c.ShopID], RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/logistics/init_parameter/get", func(c *call) (interface{}, error) {
+		var req logisticRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		o, err := s.order(c, req.OrderSN)
+		if err != nil {
+			return nil, err
+		}
+		params := o.parametersForInit()
+		return goshopee.GetParameterForInitResponse{
+			Pickup:        params["pickup"],
+			Dropoff:       params["dropoff"],
+			NonIntegrated: params["non_integrated"],
+			RequestID:     newRequestID(),
+		}, nil
+	})
+
+	s.handle("/logistics/init_info/get", func(c *call) (interface{}, error) {
+		var req logisticRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		o, err := s.order(c, req.OrderSN)
+		if err != nil {
+			return nil, err
+		}
+		resp := s.defaultLogisticInfo()
+		if o.logisticInfo != nil {
+			resp = *o.logisticInfo
+		}
+		if resp.InfoNeeded == nil {
+			resp.InfoNeeded = o.parametersForInit()
+		}
+		resp.RequestID = newRequestID()
+		return resp, nil
+	})
+
+	s.handle("/logistics/init", func(c *call) (interface{}, error) {
+		var req map[string]interface{}
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		ordersn, _ := req["ordersn"].(string)
+		o, err := s.order(c, ordersn)
+		if err != nil {
+			return nil, err
+		}
+		if o.order.Status != goshopee.OrderStatusReadyToShip && o.order.Status != goshopee.OrderStatusRetryShip {
+			return nil, errParam("order %s with status %s can not arrange shipment", ordersn, o.order.Status)
+		}
+		if o.shipped {
+			return nil, errParam("order %s logistics already initialized", ordersn)
+		}
+		var methods []string
+		for _, method := range []string{"pickup", "dropoff", "non_integrated"} {
+			if _, ok := req[method]; ok {
+				methods = append(methods, method)
+			}
+		}
+		if len(methods) != 1 {
+			return nil, errParam("exactly one of pickup, dropoff and non_integrated is required")
+		}
+		fields, ok := o.parametersForInit()[methods[0]]
+		if !ok || fields == nil {
+			return nil, errParam("%s is not available for order %s", methods[0], ordersn)
+		}
+		values, _ := req[methods[0]].(map[string]interface{})
+		for _, field := range fields {
+			if _, ok := values[field]; !ok {
+				return nil, errParam("%s.%s is required", methods[0], field)
+			}
+		}
+		o.shipped = true
+		o.order.UpdateTime = s.now()
+		if s.trackingDelay > 0 {
+			o.pendingTrackingNo = "TN" + ordersn
+			o.trackingReads = s.trackingDelay
+		} else {
+			o.order.TrackingNo = "TN" + ordersn
+		}
+		return goshopee.LogisticInitResponse{TrackingNumber: o.order.TrackingNo, RequestID: newRequestID()}, nil
+	})
+}
+
+// SetTrackingDelay makes the tracking numbers of the orders initialized
+// from now on show up only on the n-th read of the order details, as with
+// channels assigning them asynchronously. Init then returns no tracking
+// number.
+func (s *Server) SetTrackingDelay(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trackingDelay = n
+}
+
+// SetTrackingEvents seeds the tracking history GetTrackingInfo returns for
+// an order, oldest first.
+func (s *Server) SetTrackingEvents(sid uint64, ordersn string, events ...goshopee.TrackingEvent) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[sid][ordersn]
+	if !ok {
+		return false
+	}
+	o.tracking = events
+	return true
+}
+
+// readTrackingNo counts a read of the order, assigning its pending tracking
+// number once enough reads happened.
+func (o *orderState) readTrackingNo() {
+	if o.pendingTrackingNo == "" {
+		return
+	}
+	o.trackingReads--
+	if o.trackingReads <= 0 {
+		o.order.TrackingNo = o.pendingTrackingNo
+		o.pendingTrackingNo = ""
+	}
+}
+
+func (s *Server) registerTracking() {
+	s.handle("/logistics/tracking", func(c *call) (interface{}, error) {
+		var req logisticRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		o, err := s.order(c, req.OrderSN)
+		if err != nil {
+			return nil, err
+		}
+		if o.order.TrackingNo == "" {
+			status := goshopee.LogisticsStatusNotStarted
+			if o.shipped {
+				status = goshopee.LogisticsStatusRequestCreated
+			}
+			return goshopee.TrackingInfo{
+				OrderSN:         req.OrderSN,
+				LogisticsStatus: status,
+				Events:          []goshopee.TrackingEvent{},
+				RequestID:       newRequestID(),
+			}, nil
+		}
+		events := o.tracking
+		if len(events) == 0 {
+			events = []goshopee.TrackingEvent{{
+				CTime:       o.order.UpdateTime,
+				Status:      "LOGISTICS_REQUEST_CREATED",
+				Description: "Shipping label created",
+			}}
+		}
+		return goshopee.TrackingInfo{
+			OrderSN:         req.OrderSN,
+			TrackingNumber:  o.order.TrackingNo,
+			LogisticsStatus: string(events[len(events)-1].Status),
+			Events:          events,
+			RequestID:       newRequestID(),
+		}, nil
+	})
+}
+
+// airwayBillPath is where the fake serves airway bill documents. Inject a
+// Fault with this Path to make downloads fail.
+const airwayBillPath = "/airway_bill/"
+
+func (s *Server) registerAirwayBill() {
+	s.handle("/logistics/airway_bill/get_mass", func(c *call) (interface{}, error) {
+		var req struct {
+			OrderSNList []string `json:"ordersn_list"`
+			IsBatch     bool     `json:"is_batch"`
+		}
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		if len(req.OrderSNList) == 0 || len(req.OrderSNList) > 50 {
+			return nil, errParam("ordersn_list must have 1 to 50 orders")
+		}
+		var ready []string
+		errs := []goshopee.AirwayBillError{}
+		for _, ordersn := range req.OrderSNList {
+			o, ok := s.orders[c.ShopID][ordersn]
+			switch {
+			case !ok:
+				errs = append(errs, goshopee.AirwayBillError{OrderSN: ordersn, ErrorCode: "error_not_found", ErrorDescription: "order not found"})
+			case !o.shipped:
+				errs = append(errs, goshopee.AirwayBillError{OrderSN: ordersn, ErrorCode: "error_param", ErrorDescription: "logistics not initialized"})
+			default:
+				ready = append(ready, ordersn)
+			}
+		}
+
+		resp := goshopee.AirwayBillResponse{RequestID: newRequestID()}
+		if req.IsBatch {
+			bills := []string{}
+			if len(ready) > 0 {
+				bills = append(bills, s.airwayBillURL(c.Host, ready))
+			}
+			resp.BatchResult = &goshopee.AirwayBillBatchResult{TotalCount: goshopee.FlexInt(len(ready)), AirwayBills: bills, Errors: errs}
+			return resp, nil
+		}
+		bills := []goshopee.AirwayBill{}
+		for _, ordersn := range ready {
+			bills = append(bills, goshopee.AirwayBill{OrderSN: ordersn, AirwayBill: s.airwayBillURL(c.Host, []string{ordersn})})
+		}
+		resp.Result = &goshopee.AirwayBillResult{TotalCount: goshopee.FlexInt(len(ready)), AirwayBills: bills, Errors: errs}
+		return resp, nil
+	})
+}
+
+func (s *Server) airwayBillURL(host string, ordersn []string) string {
+	token := strconv.FormatUint(s.newID(), 10)
+	s.airwayBills[token] = ordersn
+	return "http://" + host + airwayBillPath + token + ".pdf"
+}
+
+// serveAirwayBill serves a minimal PDF naming the orders of the document.
+func (s *Server) serveAirwayBill(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, Request{Path: r.URL.Path})
+	if f := s.nextFault(airwayBillPath); f != nil {
+		writeError(w, &apiError{Status: f.Status, Code: f.Error, Message: f.Message})
+		return
+	}
+	token := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, airwayBillPath), ".pdf")
+	ordersn, ok := s.airwayBills[token]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	fmt.Fprintf(w, "%%PDF-1.4\n%% airway bill %s\n%%%%EOF\n", strings.Join(ordersn, ","))
+}
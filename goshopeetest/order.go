@@ -0,0 +1,374 @@
+package goshopeetest
+
+import (
+	"fmt"
+	"sort"
+
+	goshopee "github.com/passwind/go-shopee"
+)
+
+// maxTimeRange is the longest create or update time range Shopee accepts
+// when listing orders.
+const maxTimeRange = 15 * 24 * 3600
+
+// orderState is an order and its logistics data.
+type orderState struct {
+	order        goshopee.Order
+	initParams   map[string][]string
+	logisticInfo *goshopee.GetLogisticInfoResponse
+	escrow       *goshopee.EscrowDetail
+	shipped      bool
+
+	// tracking number assigned asynchronously, see SetTrackingDelay
+	pendingTrackingNo string
+	trackingReads     int
+	tracking          []goshopee.TrackingEvent
+}
+
+// AddOrder seeds an order of shop sid. The status defaults to READY_TO_SHIP,
+// the create and update times to the current time and the ship by date to
+// DaysToShip days after the creation.
+func (s *Server) AddOrder(sid uint64, order goshopee.Order) goshopee.Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if order.Status == "" {
+		order.Status = goshopee.OrderStatusReadyToShip
+	}
+	if order.CreateTime == 0 {
+		order.CreateTime = s.now()
+	}
+	if order.UpdateTime == 0 {
+		order.UpdateTime = order.CreateTime
+	}
+	if order.ShipByDate == 0 && order.DaysToShip > 0 {
+		order.ShipByDate = order.CreateTime + int64(order.DaysToShip)*24*3600
+	}
+	if s.orders[sid] == nil {
+		s.orders[sid] = map[string]*orderState{}
+	}
+	s.orders[sid][order.OrderSN] = &orderState{order: order}
+	return order
+}
+
+// Order returns the current state of an order.
+func (s *Server) Order(sid uint64, ordersn string) (goshopee.Order, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[sid][ordersn]
+	if !ok {
+		return goshopee.Order{}, false
+	}
+	return o.order, true
+}
+
+// SetOrderStatus changes the status of an order and bumps its update time,
+// as if the buyer or the carrier had acted on it.
+func (s *Server) SetOrderStatus(sid uint64, ordersn string, status goshopee.OrderStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[sid][ordersn]
+	if !ok {
+		return false
+	}
+	o.order.Status = status
+	o.order.UpdateTime = s.now()
+	return true
+}
+
+// SetEscrowDetail seeds the escrow detail GetEscrowDetail returns for an
+// order. By default it is made of the order amounts.
+func (s *Server) SetEscrowDetail(sid uint64, ordersn string, escrow goshopee.EscrowDetail) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[sid][ordersn]
+	if !ok {
+		return false
+	}
+	o.escrow = &escrow
+	return true
+}
+
+func (s *Server) order(c *call, ordersn string) (*orderState, error) {
+	o, ok := s.orders[c.ShopID][ordersn]
+	if !ok {
+		return nil, errNotFound("order %s not found", ordersn)
+	}
+	return o, nil
+}
+
+func (s *Server) registerOrder() {
+	s.handle("/orders/basics", s.listOrders)
+	s.handle("/orders/get", s.listOrdersByStatus)
+
+	s.handle("/orders/detail", func(c *call) (interface{}, error) {
+		var req struct {
+			OrderSNList []string `json:"ordersn_list"`
+		}
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		if len(req.OrderSNList) == 0 || len(req.OrderSNList) > 50 {
+			return nil, errParam("ordersn_list should have between 1 and 50 entries")
+		}
+		resp := goshopee.OrdersDetailResponse{RequestID: newRequestID()}
+		for _, sn := range req.OrderSNList {
+			o, ok := s.orders[c.ShopID][sn]
+			if !ok {
+				resp.Errors = append(resp.Errors, sn)
+				continue
+			}
+			o.readTrackingNo()
+			resp.Orders = append(resp.Orders, o.order)
+		}
+		return resp, nil
+	})
+
+	s.handle("/orders/cancel", func(c *call) (interface{}, error) {
+		var req struct {
+			OrderSN      string `json:"ordersn"`
+			CancelReason string `json:"cancel_reason"`
+		}
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		o, err := s.order(c, req.OrderSN)
+		if err != nil {
+			return nil, err
+		}
+		if o.order.Status != goshopee.OrderStatusUnpaid && o.order.Status != goshopee.OrderStatusReadyToShip {
+			return nil, errParam("order %s with status %s can not be cancelled", req.OrderSN, o.order.Status)
+		}
+		o.order.Status = goshopee.OrderStatusCancelled
+		o.order.CancelBy = "seller"
+		o.order.CancelReason = goshopee.FlexString(req.CancelReason)
+		o.order.UpdateTime = s.now()
+		return goshopee.OrderCancelResponse{ModifiedTime: uint32(o.order.UpdateTime), RequestID: newRequestID()}, nil
+	})
+
+	s.handle("/orders/note/add", func(c *call) (interface{}, error) {
+		var req struct {
+			OrderSN string `json:"ordersn"`
+			Note    string `json:"note"`
+		}
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		o, err := s.order(c, req.OrderSN)
+		if err != nil {
+			return nil, err
+		}
+		o.order.Note = req.Note
+		o.order.NoteUpdateTime = s.now()
+		return s.orderActionResponse(o), nil
+	})
+
+	s.handle("/orders/split", func(c *call) (interface{}, error) {
+		var req struct {
+			OrderSN string                      `json:"ordersn"`
+			Items   [][]goshopee.OrderSplitItem `json:"items"`
+		}
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		o, err := s.order(c, req.OrderSN)
+		if err != nil {
+			return nil, err
+		}
+		if o.order.Status != goshopee.OrderStatusReadyToShip || o.order.IsSplitUp {
+			return nil, errParam("order %s can not be split", req.OrderSN)
+		}
+		if len(req.Items) < 2 {
+			return nil, errParam("items should have at least 2 parcels")
+		}
+		resp := goshopee.OrderSplitResponse{OrderSN: req.OrderSN, RequestID: newRequestID()}
+		for i, items := range req.Items {
+			resp.Parcels = append(resp.Parcels, goshopee.OrderParcel{
+				ForderID: fmt.Sprintf("%s-%d", req.OrderSN, i+1),
+				Items:    items,
+			})
+		}
+		o.order.IsSplitUp = true
+		o.order.UpdateTime = s.now()
+		return resp, nil
+	})
+
+	s.handle("/orders/unsplit", func(c *call) (interface{}, error) {
+		o, err := s.orderOfCall(c)
+		if err != nil {
+			return nil, err
+		}
+		if !o.order.IsSplitUp {
+			return nil, errParam("order %s is not split", o.order.OrderSN)
+		}
+		o.order.IsSplitUp = false
+		o.order.UpdateTime = s.now()
+		return s.orderActionResponse(o), nil
+	})
+
+	s.handle("/orders/buyer_cancellation/accept", func(c *call) (interface{}, error) {
+		o, err := s.orderOfCall(c)
+		if err != nil {
+			return nil, err
+		}
+		if o.order.Status != goshopee.OrderStatusInCancel {
+			return nil, errParam("order %s has no buyer cancellation", o.order.OrderSN)
+		}
+		o.order.Status = goshopee.OrderStatusCancelled
+		o.order.CancelBy = "buyer"
+		o.order.UpdateTime = s.now()
+		return s.orderActionResponse(o), nil
+	})
+
+	s.handle("/orders/buyer_cancellation/reject", func(c *call) (interface{}, error) {
+		o, err := s.orderOfCall(c)
+		if err != nil {
+			return nil, err
+		}
+		if o.order.Status != goshopee.OrderStatusInCancel {
+			return nil, errParam("order %s has no buyer cancellation", o.order.OrderSN)
+		}
+		o.order.Status = goshopee.OrderStatusReadyToShip
+		o.order.UpdateTime = s.now()
+		return s.orderActionResponse(o), nil
+	})
+
+	s.handle("/orders/my_income", func(c *call) (interface{}, error) {
+		o, err := s.orderOfCall(c)
+		if err != nil {
+			return nil, err
+		}
+		escrow := goshopee.EscrowDetail{
+			OrderSN:       o.order.OrderSN,
+			BuyerUserName: o.order.BuyerUsername,
+			IncomeDetails: goshopee.EscrowIncomeDetails{
+				LocalCurrency: o.order.Currency,
+				TotalAmount:   o.order.TotalAmount,
+				EscrowAmount:  o.order.EscrowAmount,
+			},
+		}
+		if o.escrow != nil {
+			escrow = *o.escrow
+		}
+		return goshopee.EscrowDetailResponse{Order: &escrow, RequestID: newRequestID()}, nil
+	})
+}
+
+// orderOfCall returns the order of a call with an ordersn parameter.
+func (s *Server) orderOfCall(c *call) (*orderState, error) {
+	var req struct {
+		OrderSN string `json:"ordersn"`
+	}
+	if err := c.decode(&req); err != nil {
+		return nil, err
+	}
+	return s.order(c, req.OrderSN)
+}
+
+func (s *Server) orderActionResponse(o *orderState) goshopee.OrderActionResponse {
+	return goshopee.OrderActionResponse{
+		OrderSN:      o.order.OrderSN,
+		ModifiedTime: uint32(s.now()),
+		RequestID:    newRequestID(),
+	}
+}
+
+// listOrdersRequest is the body of the order listings.
+type listOrdersRequest struct {
+	CreateTimeFrom int64                `json:"create_time_from"`
+	CreateTimeTo   int64                `json:"create_time_to"`
+	UpdateTimeFrom int64                `json:"update_time_from"`
+	UpdateTimeTo   int64                `json:"update_time_to"`
+	Offset         int                  `json:"pagination_offset"`
+	Limit          int                  `json:"pagination_entries_per_page"`
+	OrderStatus    goshopee.OrderStatus `json:"order_status"`
+}
+
+func (r *listOrdersRequest) byUpdate() bool {
+	return r.UpdateTimeFrom > 0 || r.UpdateTimeTo > 0
+}
+
+func (r *listOrdersRequest) byCreate() bool {
+	return r.CreateTimeFrom > 0 || r.CreateTimeTo > 0
+}
+
+// listOrders serves /orders/basics, which lists by create or update time
+// and has no status parameter
+// https://open.shopee.com/documents?module=4&type=1&id=399
+func (s *Server) listOrders(c *call) (interface{}, error) {
+	var req listOrdersRequest
+	if err := c.decode(&req); err != nil {
+		return nil, err
+	}
+	if req.byUpdate() && req.byCreate() {
+		return nil, errParam("create_time and update_time ranges cannot be used together")
+	}
+	if req.byUpdate() {
+		return s.orderPage(c, req, req.UpdateTimeFrom, req.UpdateTimeTo, true, "")
+	}
+	return s.orderPage(c, req, req.CreateTimeFrom, req.CreateTimeTo, false, "")
+}
+
+// listOrdersByStatus serves /orders/get, which lists by status and create
+// time only
+// https://open.shopee.com/documents?module=4&type=1&id=398
+func (s *Server) listOrdersByStatus(c *call) (interface{}, error) {
+	var req listOrdersRequest
+	if err := c.decode(&req); err != nil {
+		return nil, err
+	}
+	if req.OrderStatus == "" {
+		return nil, errParam("order_status is required")
+	}
+	if req.byUpdate() {
+		return nil, errParam("update_time is not supported, use create_time_from and create_time_to")
+	}
+	return s.orderPage(c, req, req.CreateTimeFrom, req.CreateTimeTo, false, req.OrderStatus)
+}
+
+// orderPage returns a page of the orders of the shop created, or updated,
+// within [from, to] and having status, any status when empty or ALL.
+func (s *Server) orderPage(c *call, req listOrdersRequest, from, to int64, byUpdate bool, status goshopee.OrderStatus) (interface{}, error) {
+	if from == 0 || to == 0 {
+		return nil, errParam("time range is required")
+	}
+	if to < from || to-from > maxTimeRange {
+		return nil, errParam("time range should be within 15 days")
+	}
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+	if req.Limit > 100 {
+		return nil, errParam("pagination_entries_per_page should be no more than 100")
+	}
+
+	var orders []goshopee.Order
+	for _, o := range s.orders[c.ShopID] {
+		t := o.order.CreateTime
+		if byUpdate {
+			t = o.order.UpdateTime
+		}
+		if t < from || t > to {
+			continue
+		}
+		if status != "" && status != "ALL" && o.order.Status != status {
+			continue
+		}
+		orders = append(orders, goshopee.Order{
+			OrderSN:    o.order.OrderSN,
+			Status:     o.order.Status,
+			UpdateTime: o.order.UpdateTime,
+		})
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].OrderSN < orders[j].OrderSN })
+
+	resp := goshopee.OrdersResponse{RequestID: newRequestID()}
+	if req.Offset < len(orders) {
+		end := req.Offset + req.Limit
+		if end > len(orders) {
+			end = len(orders)
+		}
+		resp.Orders = orders[req.Offset:end]
+		resp.More = end < len(orders)
+	}
+	return resp, nil
+}
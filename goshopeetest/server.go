@@ -0,0 +1,335 @@
+// Package goshopeetest provides an in-process fake of the Shopee Open API for
+// testing code built on goshopee.
+//
+// The fake keeps shops, items, orders, logistics and discounts in memory,
+// verifies request signatures the same way Shopee does, and can be told to
+// fail or rate limit the next calls to an endpoint:
+//
+//	srv := goshopeetest.NewServer(1000, "secret")
+//	defer srv.Close()
+//
+//	srv.AddShop(goshopee.Shop{ID: 1, Name: "test shop"})
+//	srv.InjectRateLimit("/shop/get", 1, 0)
+//
+//	client := srv.Client(goshopee.WithRetry(2))
+//	shop, err := client.Shop.Get(1)
+package goshopeetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goshopee "github.com/passwind/go-shopee"
+)
+
+const apiPathPrefix = "/api/v1"
+
+// Server is a stateful fake Shopee API listening on a local address.
+type Server struct {
+	*httptest.Server
+
+	PartnerID  int
+	PartnerKey string
+
+	mu       sync.Mutex
+	clock    goshopee.Clock
+	nextID   uint64
+	handlers map[string]handlerFunc
+	faults   map[string][]*Fault
+	requests []Request
+
+	shops      map[uint64]*goshopee.Shop
+	categories map[uint64][]goshopee.ItemCategory
+	attributes map[uint64][]goshopee.ItemAttribute
+	items      map[uint64]map[uint64]*itemState
+	orders     map[uint64]map[string]*orderState
+	logistics  map[uint64][]goshopee.Logistic
+	discounts  map[uint64]map[uint64]*discountState
+
+	airwayBills   map[string][]string // download token to ordersn
+	trackingDelay int
+}
+
+// Request is a call received by the fake server.
+type Request struct {
+	Path   string
+	ShopID uint64
+	Body   []byte
+}
+
+// Fault describes an error returned instead of handling a call. A Status of
+// http.StatusOK with an Error code makes the fake answer with a Shopee error
+// envelope, any other status is returned as is.
+type Fault struct {
+	Path       string
+	Status     int
+	Error      string
+	Message    string
+	RetryAfter int
+
+	// Times is the number of calls to fail, zero or less fails them all.
+	Times int
+}
+
+// apiError is a Shopee error envelope returned by a handler.
+type apiError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func errParam(format string, v ...interface{}) *apiError {
+	return &apiError{Status: http.StatusOK, Code: "error_param", Message: fmt.Sprintf(format, v...)}
+}
+
+func errNotFound(format string, v ...interface{}) *apiError {
+	return &apiError{Status: http.StatusOK, Code: "error_not_found", Message: fmt.Sprintf(format, v...)}
+}
+
+// call is the decoded envelope of an incoming request.
+type call struct {
+	ShopID uint64
+	Body   []byte
+	Host   string
+}
+
+func (c *call) decode(v interface{}) error {
+	if err := json.Unmarshal(c.Body, v); err != nil {
+		return errParam("invalid request body: %s", err)
+	}
+	return nil
+}
+
+// handlerFunc handles a call while holding the server lock.
+type handlerFunc func(c *call) (interface{}, error)
+
+// NewServer starts a fake Shopee API accepting requests signed with the
+// given partner credentials. The caller must Close it when done.
+func NewServer(partnerID int, partnerKey string) *Server {
+	s := &Server{
+		PartnerID:  partnerID,
+		PartnerKey: partnerKey,
+		clock:      goshopee.ClockFunc(time.Now),
+		nextID:     1000,
+		faults:     map[string][]*Fault{},
+		shops:      map[uint64]*goshopee.Shop{},
+		categories: map[uint64][]goshopee.ItemCategory{},
+		attributes: map[uint64][]goshopee.ItemAttribute{},
+		items:      map[uint64]map[uint64]*itemState{},
+		orders:     map[uint64]map[string]*orderState{},
+		logistics:  map[uint64][]goshopee.Logistic{},
+		discounts:  map[uint64]map[uint64]*discountState{},
+
+		airwayBills: map[string][]string{},
+	}
+	s.handlers = map[string]handlerFunc{}
+	s.registerShop()
+	s.registerItem()
+	s.registerOrder()
+	s.registerLogistic()
+	s.registerDiscount()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// App returns the goshopee.App pointing at the fake server.
+func (s *Server) App() goshopee.App {
+	return goshopee.App{
+		PartnerID:  s.PartnerID,
+		PartnerKey: s.PartnerKey,
+		APIURL:     s.URL,
+	}
+}
+
+// Client returns a goshopee.Client talking to the fake server.
+func (s *Server) Client(opts ...goshopee.Option) *goshopee.Client {
+	return goshopee.NewClient(s.App(), opts...)
+}
+
+// SetClock sets the clock used for create and update times of the fake data.
+func (s *Server) SetClock(clock goshopee.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+}
+
+// Inject registers a fault for f.Path, e.g. "/items/get".
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := normalizePath(f.Path)
+	fault := f
+	s.faults[path] = append(s.faults[path], &fault)
+}
+
+// InjectError makes the next times calls to path answer with a Shopee error
+// envelope, e.g. InjectError("/item/add", 1, "error_param", "invalid price").
+func (s *Server) InjectError(path string, times int, code, message string) {
+	s.Inject(Fault{Path: path, Times: times, Status: http.StatusOK, Error: code, Message: message})
+}
+
+// InjectRateLimit makes the next times calls to path answer with a
+// 429 Too Many Requests and the given Retry-After seconds.
+func (s *Server) InjectRateLimit(path string, times, retryAfter int) {
+	s.Inject(Fault{
+		Path:       path,
+		Times:      times,
+		Status:     http.StatusTooManyRequests,
+		Error:      "error_too_many_request",
+		Message:    "too many requests",
+		RetryAfter: retryAfter,
+	})
+}
+
+// ClearFaults removes every pending fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = map[string][]*Fault{}
+}
+
+// Requests returns the calls received so far, including the rejected ones.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, airwayBillPath) {
+		s.serveAirwayBill(w, r)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, &apiError{Status: http.StatusBadRequest, Code: "error_param", Message: err.Error()})
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, apiPathPrefix)
+	c := &call{Body: body, Host: r.Host}
+	var envelope struct {
+		PartnerID int         `json:"partner_id"`
+		ShopID    json.Number `json:"shopid"`
+		Timestamp int64       `json:"timestamp"`
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			writeError(w, errParam("invalid request body: %s", err))
+			return
+		}
+	}
+	if sid, err := strconv.ParseUint(envelope.ShopID.String(), 10, 64); err == nil {
+		c.ShopID = sid
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, Request{Path: path, ShopID: c.ShopID, Body: body})
+
+	if r.Method != http.MethodPost {
+		writeError(w, &apiError{Status: http.StatusMethodNotAllowed, Code: "error_method", Message: r.Method})
+		return
+	}
+
+	url := "http://" + r.Host + r.URL.RequestURI()
+	if !goshopee.VerifyPushMsg(url, string(body), s.PartnerKey, r.Header.Get("Authorization")) {
+		writeError(w, &apiError{Status: http.StatusForbidden, Code: "error_auth", Message: "Invalid signature"})
+		return
+	}
+	if envelope.PartnerID != s.PartnerID {
+		writeError(w, &apiError{Status: http.StatusForbidden, Code: "error_auth", Message: "Invalid partner_id"})
+		return
+	}
+	if envelope.Timestamp == 0 {
+		writeError(w, errParam("timestamp is required"))
+		return
+	}
+
+	if f := s.nextFault(path); f != nil {
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(f.RetryAfter))
+		}
+		writeError(w, &apiError{Status: f.Status, Code: f.Error, Message: f.Message})
+		return
+	}
+
+	h, ok := s.handlers[path]
+	if !ok {
+		writeError(w, &apiError{Status: http.StatusNotFound, Code: "error_not_found", Message: "unknown api " + path})
+		return
+	}
+	resp, err := h(c)
+	if err != nil {
+		if e, ok := err.(*apiError); ok {
+			writeError(w, e)
+			return
+		}
+		writeError(w, &apiError{Status: http.StatusInternalServerError, Code: "error_server", Message: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// nextFault pops the fault to apply to a call on path, if any.
+func (s *Server) nextFault(path string) *Fault {
+	faults := s.faults[path]
+	if len(faults) == 0 {
+		return nil
+	}
+	f := faults[0]
+	if f.Times > 0 {
+		f.Times--
+		if f.Times == 0 {
+			s.faults[path] = faults[1:]
+		}
+	}
+	return f
+}
+
+func (s *Server) now() int64 {
+	return s.clock.Now().Unix()
+}
+
+func (s *Server) newID() uint64 {
+	s.nextID++
+	return s.nextID
+}
+
+func (s *Server) handle(path string, h handlerFunc) {
+	s.handlers[normalizePath(path)] = h
+}
+
+func normalizePath(path string) string {
+	return "/" + strings.TrimLeft(path, "/")
+}
+
+func newRequestID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 16)
+}
+
+func writeError(w http.ResponseWriter, e *apiError) {
+	writeJSON(w, e.Status, map[string]interface{}{
+		"error":      e.Code,
+		"msg":        e.Message,
+		"request_id": newRequestID(),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
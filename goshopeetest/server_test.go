@@ -0,0 +1,209 @@
+package goshopeetest_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+)
+
+func TestServerSignature(t *testing.T) {
+	cases := []struct {
+		name       string
+		partnerID  int
+		partnerKey string
+		wantStatus int
+	}{
+		{"valid", 1, "key", 0},
+		{"wrong key", 1, "other", http.StatusForbidden},
+		{"wrong partner", 2, "key", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			srv.AddShop(goshopee.Shop{ID: 7, Name: "shop"})
+
+			app := srv.App()
+			app.PartnerID = tc.partnerID
+			app.PartnerKey = tc.partnerKey
+			_, err := goshopee.NewClient(app).Shop.Get(7)
+			if tc.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("Get: %v", err)
+				}
+			} else if e, ok := err.(goshopee.ResponseError); !ok || e.Status != tc.wantStatus {
+				t.Fatalf("Get error = %v, want status %d", err, tc.wantStatus)
+			}
+			if reqs := srv.Requests(); len(reqs) != 1 || reqs[0].Path != "/shop/get" || reqs[0].ShopID != 7 {
+				t.Errorf("Requests = %+v, want the call to /shop/get of shop 7", reqs)
+			}
+		})
+	}
+}
+
+func TestServerInjectError(t *testing.T) {
+	srv := goshopeetest.NewServer(1, "key")
+	defer srv.Close()
+	srv.AddShop(goshopee.Shop{ID: 7})
+	srv.InjectError("/shop/get", 2, "error_param", "shop is busy")
+	client := srv.Client()
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Shop.Get(7)
+		if e, ok := err.(goshopee.ResponseError); !ok || e.Message != "error_param[shop is busy]" {
+			t.Fatalf("call %d error = %v, want the injected error", i, err)
+		}
+	}
+	if _, err := client.Shop.Get(7); err != nil {
+		t.Fatalf("Get after the faults: %v", err)
+	}
+
+	srv.InjectError("/shop/get", 0, "error_server", "down")
+	for i := 0; i < 3; i++ {
+		if _, err := client.Shop.Get(7); err == nil {
+			t.Fatalf("call %d succeeded, want a fault until cleared", i)
+		}
+	}
+	srv.ClearFaults()
+	if _, err := client.Shop.Get(7); err != nil {
+		t.Fatalf("Get after ClearFaults: %v", err)
+	}
+}
+
+func TestServerInjectRateLimit(t *testing.T) {
+	srv := goshopeetest.NewServer(1, "key")
+	defer srv.Close()
+	srv.AddShop(goshopee.Shop{ID: 7})
+	srv.InjectRateLimit("/shop/get", 1, 3)
+
+	_, err := srv.Client().Shop.Get(7)
+	e, ok := err.(goshopee.RateLimitError)
+	if !ok || e.Status != http.StatusTooManyRequests || e.RetryAfter != 3 {
+		t.Fatalf("Get error = %#v, want a 429 with Retry-After 3", err)
+	}
+	if _, err := srv.Client().Shop.Get(7); err != nil {
+		t.Fatalf("Get after the fault: %v", err)
+	}
+}
+
+func TestServerListOrders(t *testing.T) {
+	const day = 24 * 3600
+	now := time.Now().Unix()
+	srv := goshopeetest.NewServer(1, "key")
+	defer srv.Close()
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN1", CreateTime: now - 20*day, UpdateTime: now - day})
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN2", CreateTime: now - day, Status: goshopee.OrderStatusCompleted})
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN3", CreateTime: now - day})
+	client := srv.Client()
+
+	cases := []struct {
+		name    string
+		path    string
+		params  map[string]interface{}
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "by create time",
+			path:   "/orders/basics",
+			params: map[string]interface{}{"create_time_from": now - 2*day, "create_time_to": now},
+			want:   []string{"SN2", "SN3"},
+		},
+		{
+			name:   "by update time",
+			path:   "/orders/basics",
+			params: map[string]interface{}{"update_time_from": now - 2*day, "update_time_to": now},
+			want:   []string{"SN1", "SN2", "SN3"},
+		},
+		{
+			name:   "status is not a basics parameter",
+			path:   "/orders/basics",
+			params: map[string]interface{}{"create_time_from": now - 2*day, "create_time_to": now, "order_status": "COMPLETED"},
+			want:   []string{"SN2", "SN3"},
+		},
+		{
+			name:    "no time range",
+			path:    "/orders/basics",
+			params:  map[string]interface{}{},
+			wantErr: true,
+		},
+		{
+			name:    "half a time range",
+			path:    "/orders/basics",
+			params:  map[string]interface{}{"create_time_from": now - day},
+			wantErr: true,
+		},
+		{
+			name:    "longer than 15 days",
+			path:    "/orders/basics",
+			params:  map[string]interface{}{"create_time_from": now - 16*day, "create_time_to": now},
+			wantErr: true,
+		},
+		{
+			name:    "inverted",
+			path:    "/orders/basics",
+			params:  map[string]interface{}{"create_time_from": now, "create_time_to": now - day},
+			wantErr: true,
+		},
+		{
+			name: "create and update ranges",
+			path: "/orders/basics",
+			params: map[string]interface{}{
+				"create_time_from": now - day, "create_time_to": now,
+				"update_time_from": now - day, "update_time_to": now,
+			},
+			wantErr: true,
+		},
+		{
+			name:   "by status",
+			path:   "/orders/get",
+			params: map[string]interface{}{"create_time_from": now - 2*day, "create_time_to": now, "order_status": "COMPLETED"},
+			want:   []string{"SN2"},
+		},
+		{
+			name:   "all statuses",
+			path:   "/orders/get",
+			params: map[string]interface{}{"create_time_from": now - 2*day, "create_time_to": now, "order_status": "ALL"},
+			want:   []string{"SN2", "SN3"},
+		},
+		{
+			name:    "status required",
+			path:    "/orders/get",
+			params:  map[string]interface{}{"create_time_from": now - 2*day, "create_time_to": now},
+			wantErr: true,
+		},
+		{
+			name:    "status by update time",
+			path:    "/orders/get",
+			params:  map[string]interface{}{"update_time_from": now - 2*day, "update_time_to": now, "order_status": "COMPLETED"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resp goshopee.OrdersResponse
+			err := client.CallShop(context.Background(), 7, tc.path, tc.params, &resp)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("CallShop = %+v, want an error", resp.Orders)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CallShop: %v", err)
+			}
+			var got []string
+			for _, o := range resp.Orders {
+				got = append(got, o.OrderSN)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("orders = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
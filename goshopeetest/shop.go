@@ -0,0 +1,34 @@
+package goshopeetest
+
+import goshopee "github.com/passwind/go-shopee"
+
+// AddShop seeds a shop, replacing any shop with the same ID.
+func (s *Server) AddShop(shop goshopee.Shop) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if shop.Status == "" {
+		shop.Status = "NORMAL"
+	}
+	s.shops[shop.ID] = &shop
+}
+
+// shop returns the shop of the call or an error when it was not seeded.
+func (s *Server) shop(c *call) (*goshopee.Shop, error) {
+	shop, ok := s.shops[c.ShopID]
+	if !ok {
+		return nil, errNotFound("shop %d not found", c.ShopID)
+	}
+	return shop, nil
+}
+
+func (s *Server) registerShop() {
+	s.handle("/shop/get", func(c *call) (interface{}, error) {
+		shop, err := s.shop(c)
+		if err != nil {
+			return nil, err
+		}
+		resp := *shop
+		resp.RequestID = newRequestID()
+		return resp, nil
+	})
+}
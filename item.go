@@ -1,14 +1,19 @@
 package goshopee
 
+import "context"
+
 type ItemService interface {
-	List(interface{}) ([]Item, error)
+	List(ctx context.Context, sid uint64, options *ItemListOptions) ([]Item, error)
 	ListWithPagination(sid uint64, offset, limit uint32, options interface{}) ([]Item, *Pagination, error)
-	Count(interface{}) (int, error)
+	Count(ctx context.Context, sid uint64, options *ItemListOptions) (int, error)
+	Iter(ctx context.Context, sid uint64, options *ItemListOptions, from Cursor) *Pager[Item]
 	Get(uint64, uint64) (*Item, error)
 	Create(newItem ItemOper) (*Item, error)
 	Update(ItemBase) (*Item, error)
-	UpdatePrice(sid, itemid uint64, price float64) (*ItemPriceOper, error)
+	UpdatePrice(sid, itemid uint64, price Decimal) (*ItemPriceOper, error)
 	UpdateStock(sid, itemid uint64, stock uint32) (*ItemStockOper, error)
+	UpdateLogistics(ctx context.Context, sid, itemid uint64, logistics []ItemLogistic) (*Item, error)
+	UpdateLogisticsAll(ctx context.Context, sid uint64, change ItemLogistic, options *ItemListOptions) ([]ItemLogisticsResult, error)
 	Delete(sid, itemid uint64) error
 	UnlistItem(sid, itemid uint64, unlist bool) ([]UnlistItemSuccess, []UnlistItemFailed, error)
 	InitTierVariation(sid, itemid uint64, tierVariations []TierVariation, variations []TierVariationOperDef) ([]Variation, error)
@@ -27,14 +32,14 @@ type ItemBase struct {
 	Name                  string      `json:"name"`
 	Description           string      `json:"description"`
 	Currency              string      `json:"currency"`
-	HasVariation          bool        `json:"has_variation"`
-	Price                 float64     `json:"price"`
+	HasVariation          FlexBool    `json:"has_variation"`
+	Price                 Decimal     `json:"price"`
 	Stock                 uint32      `json:"stock"`
 	CreateTime            uint32      `json:"create_time"`
 	UpdateTime            uint32      `json:"update_time"`
 	Weight                float64     `json:"weight"`
 	CategoryID            uint64      `json:"category_id"`
-	OriginalPrice         float64     `json:"original_price"`
+	OriginalPrice         Decimal     `json:"original_price"`
 	Variations            []Variation `json:"variations,omitempty"`
 	Attributes            []Attribute `json:"attributes"`
 	Logistics             []Logistic  `json:"logistics"`
@@ -51,13 +56,13 @@ type ItemBase struct {
 	SizeChart             string      `json:"size_chart"`
 	Condition             string      `json:"condition"`
 	DiscountID            uint64      `json:"discount_id"`
-	Is2tierItem           bool        `json:"is_2tier_item"`
+	Is2tierItem           FlexBool    `json:"is_2tier_item"`
 	Tenures               []uint32    `json:"tenures"`
 	ReservedStock         uint32      `json:"reserved_stock"`
-	IsPreOrder            bool        `json:"is_pre_order"`
-	InflatedPrice         float64     `json:"inflated_price"`
-	InflatedOriginalPrice float64     `json:"inflated_original_price"`
-	SipItemPrice          float64     `json:"sip_item_price"`
+	IsPreOrder            FlexBool    `json:"is_pre_order"`
+	InflatedPrice         Decimal     `json:"inflated_price"`
+	InflatedOriginalPrice Decimal     `json:"inflated_original_price"`
+	SipItemPrice          Decimal     `json:"sip_item_price"`
 	PriceSource           string      `json:"price_source"`
 }
 
@@ -65,6 +70,9 @@ type Item struct {
 	ItemBase
 
 	Images []string `json:"images"`
+
+	// Raw is only set with WithRawFields.
+	Raw *RawFields `json:"-"`
 }
 
 // ItemOper from https://open.shopee.com/documents?module=2&type=1&id=365
@@ -77,21 +85,21 @@ type ItemOper struct {
 type Wholesale struct {
 	Min       uint32  `json:"min"`
 	Max       uint32  `json:"max"`
-	UnitPrice float64 `json:"unit_price"`
+	UnitPrice Decimal `json:"unit_price"`
 }
 
 type ItemResponse struct {
-	ItemID    uint64 `json:"item_id"`
-	RequestID string `json:"request_id"`
+	ItemID    FlexInt `json:"item_id"`
+	RequestID string  `json:"request_id"`
 }
 
 // ItemsResponse Represents the result from the GetItemsList endpoint
 // https://open.shopee.com/documents?module=2&type=1&id=375
 type ItemsResponse struct {
-	Items     []Item `json:"items"`
-	More      bool   `json:"more"`
-	Total     uint32 `json:"total"`
-	RequestID string `json:"request_id"`
+	Items     []Item  `json:"items"`
+	More      bool    `json:"more"`
+	Total     FlexInt `json:"total"`
+	RequestID string  `json:"request_id"`
 }
 
 // Pagination of results
@@ -108,38 +116,118 @@ type ItemServiceOp struct {
 	client *Client
 }
 
-func (s *ItemServiceOp) List(options interface{}) ([]Item, error) {
-	// TODO:
-	return nil, nil
+// maxItemsPerPage is the largest page size accepted by /items/get.
+const maxItemsPerPage = 100
+
+// ItemListOptions filters the items returned by List, Count and
+// ListWithPagination.
+type ItemListOptions struct {
+	UpdateTimeFrom  uint32   `json:"update_time_from,omitempty"`
+	UpdateTimeTo    uint32   `json:"update_time_to,omitempty"`
+	ItemStatus      []string `json:"item_status,omitempty"` // e.g. NORMAL, UNLIST, BANNED, DELETED
+	NeedDeletedItem bool     `json:"need_deleted_item,omitempty"`
+}
+
+// List returns every item of the shop matching options, fetching the pages
+// of /items/get until there are no more.
+func (s *ItemServiceOp) List(ctx context.Context, sid uint64, options *ItemListOptions) ([]Item, error) {
+	var items []Item
+	var offset uint32
+	for {
+		if err := ctx.Err(); err != nil {
+			return items, err
+		}
+		page, pagination, err := s.listPage(ctx, sid, offset, maxItemsPerPage, options)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, page...)
+		if !pagination.More || len(page) == 0 {
+			return items, nil
+		}
+		offset += uint32(len(page))
+	}
+}
+
+// Iter returns a Pager lazily iterating over the items of the shop matching
+// options, starting at from:
+//
+//	pager := client.Item.Iter(ctx, sid, nil, goshopee.Cursor{})
+//	for item, err := range pager.All() {
+//		...
+//	}
+func (s *ItemServiceOp) Iter(ctx context.Context, sid uint64, options *ItemListOptions, from Cursor) *Pager[Item] {
+	fetch := func(ctx context.Context, offset uint32) ([]Item, bool, error) {
+		items, pagination, err := s.listPage(ctx, sid, offset, maxItemsPerPage, options)
+		if err != nil {
+			return nil, false, err
+		}
+		return items, pagination.More, nil
+	}
+	return NewPager(ctx, fetch, from)
 }
 
 // ListWithPagination https://open.shopee.com/documents?module=2&type=1&id=375
+// The options are an *ItemListOptions or a map of raw request parameters.
 func (s *ItemServiceOp) ListWithPagination(sid uint64, offset, limit uint32, options interface{}) ([]Item, *Pagination, error) {
+	return s.listPage(context.Background(), sid, offset, limit, options)
+}
+
+func (s *ItemServiceOp) listPage(ctx context.Context, sid uint64, offset, limit uint32, options interface{}) ([]Item, *Pagination, error) {
 	path := "/items/get"
 	wrappedData := map[string]interface{}{
 		"pagination_offset":           offset,
 		"pagination_entries_per_page": limit,
 		"shopid":                      sid,
 	}
+	opts, err := itemListParams(options)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range opts {
+		wrappedData[k] = v
+	}
 	resource := new(ItemsResponse)
-	err := s.client.Post(path, wrappedData, resource)
+	err = s.client.Call(ctx, path, wrappedData, resource)
 	page := &Pagination{
 		Offset:   offset,
 		PageSize: limit,
-		Total:    resource.Total,
+		Total:    uint32(resource.Total),
 		More:     resource.More,
 	}
 	return resource.Items, page, err
 }
 
-func (s *ItemServiceOp) Count(interface{}) (int, error) {
-	return 0, nil
+func itemListParams(options interface{}) (map[string]interface{}, error) {
+	switch opts := options.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return opts, nil
+	case *ItemListOptions:
+		if opts == nil {
+			return nil, nil
+		}
+		return ToMapData(opts)
+	default:
+		return ToMapData(opts)
+	}
+}
+
+// Count returns the number of items of the shop matching options, as
+// reported by /items/get.
+func (s *ItemServiceOp) Count(ctx context.Context, sid uint64, options *ItemListOptions) (int, error) {
+	_, pagination, err := s.listPage(ctx, sid, 0, 1, options)
+	if err != nil {
+		return 0, err
+	}
+	return int(pagination.Total), nil
 }
 
 type ItemDetailResponse struct {
-	ItemID  uint64 `json:"item_id"`
-	Item    *Item  `json:"item"`
-	Warning string `json:"warning"`
+	ItemID  FlexInt `json:"item_id"`
+	Item    *Item   `json:"item"`
+	Warning string  `json:"warning"`
 }
 
 func (s *ItemServiceOp) Get(sid, itemid uint64) (*Item, error) {
@@ -154,7 +242,7 @@ func (s *ItemServiceOp) Get(sid, itemid uint64) (*Item, error) {
 }
 
 type ItemOperResponse struct {
-	ItemID    uint64   `json:"item_id"`
+	ItemID    FlexInt  `json:"item_id"`
 	Item      *Item    `json:"item"`
 	SizeChart string   `json:"size_chart,omitempty"`
 	Warning   string   `json:"warning"`
@@ -162,7 +250,13 @@ type ItemOperResponse struct {
 }
 
 // Create https://open.shopee.com/documents?module=2&type=1&id=365
+// The logistics of the item are only checked by Shopee, unless the client is
+// created with WithItemLogisticsValidation or ValidateItemLogistics is called
+// beforehand.
 func (s *ItemServiceOp) Create(newItem ItemOper) (*Item, error) {
+	if err := s.checkLogistics(&newItem, true); err != nil {
+		return nil, err
+	}
 	path := "/item/add"
 	wrappedData, err := ToMapData(newItem)
 	resource := new(ItemOperResponse)
@@ -174,7 +268,13 @@ func (s *ItemServiceOp) Create(newItem ItemOper) (*Item, error) {
 }
 
 // Update https://open.shopee.com/documents?module=2&type=1&id=376
+// The logistics of the item are only checked by Shopee, unless the client is
+// created with WithItemLogisticsValidation or ValidateItemLogistics is called
+// beforehand.
 func (s *ItemServiceOp) Update(updItem ItemBase) (*Item, error) {
+	if err := s.checkLogistics(&ItemOper{ItemBase: updItem}, false); err != nil {
+		return nil, err
+	}
 	path := "/item/update"
 	wrappedData, err := ToMapData(updItem)
 	resource := new(ItemOperResponse)
@@ -186,9 +286,9 @@ func (s *ItemServiceOp) Update(updItem ItemBase) (*Item, error) {
 }
 
 type ItemDeleteResponse struct {
-	ItemID    uint64 `json:"item_id"`
-	Msg       string `json:"msg"`
-	RequestID string `json:"request_id"`
+	ItemID    FlexInt `json:"item_id"`
+	Msg       string  `json:"msg"`
+	RequestID string  `json:"request_id"`
 }
 
 // Delete https://open.shopee.com/documents?module=2&type=1&id=369
@@ -204,10 +304,10 @@ func (s *ItemServiceOp) Delete(sid, itemid uint64) error {
 }
 
 type ItemPriceOper struct {
-	ID            uint64  `json:"item_id"`
+	ID            FlexInt `json:"item_id"`
 	ModifiedTime  uint32  `json:"modified_time"`
-	Price         float64 `json:"price"`
-	InflatedPrice float64 `json:"inflated_price"`
+	Price         Decimal `json:"price"`
+	InflatedPrice Decimal `json:"inflated_price"`
 }
 
 type ItemPriceOperResponse struct {
@@ -216,7 +316,7 @@ type ItemPriceOperResponse struct {
 }
 
 // UpdatePrice https://open.shopee.com/documents?module=2&type=1&id=377
-func (s *ItemServiceOp) UpdatePrice(sid, itemid uint64, price float64) (*ItemPriceOper, error) {
+func (s *ItemServiceOp) UpdatePrice(sid, itemid uint64, price Decimal) (*ItemPriceOper, error) {
 	path := "/items/update_price"
 	wrappedData := map[string]interface{}{
 		"item_id": itemid,
@@ -229,9 +329,9 @@ func (s *ItemServiceOp) UpdatePrice(sid, itemid uint64, price float64) (*ItemPri
 }
 
 type ItemStockOper struct {
-	ID           uint64 `json:"item_id"`
-	ModifiedTime uint32 `json:"modified_time"`
-	Stock        uint32 `json:"stock"`
+	ID           FlexInt `json:"item_id"`
+	ModifiedTime uint32  `json:"modified_time"`
+	Stock        FlexInt `json:"stock"`
 }
 
 type ItemStockOperResponse struct {
@@ -253,13 +353,13 @@ func (s *ItemServiceOp) UpdateStock(sid, itemid uint64, stock uint32) (*ItemStoc
 }
 
 type UnlistItemFailed struct {
-	ItemID           uint64 `json:"item_id"`
-	ErrorDescription string `json:"error_description"`
+	ItemID           FlexInt `json:"item_id"`
+	ErrorDescription string  `json:"error_description"`
 }
 
 type UnlistItemSuccess struct {
-	ItemID uint64 `json:"item_id"`
-	Unlist bool   `json:"unlist"`
+	ItemID FlexInt `json:"item_id"`
+	Unlist bool    `json:"unlist"`
 }
 
 type UnlistResponse struct {
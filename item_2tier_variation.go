@@ -10,7 +10,7 @@ type TierVariation struct {
 
 type TierVariationOperDef struct {
 	Stock     uint32   `json:"stock"`
-	Price     float64  `json:"price"`
+	Price     Decimal  `json:"price"`
 	TierIndex []uint32 `json:"tier_index"`
 }
 
@@ -21,7 +21,7 @@ type TierVariationIndexOperDef struct {
 
 type TierVariationOperResponse struct {
 	RequestID       string          `json:"request_id"`
-	ItemID          uint64          `json:"item_id"` // help doc is uint32, in fact is uint64
+	ItemID          FlexInt         `json:"item_id"` // help doc is uint32, in fact is uint64 and sometimes a string
 	TierVariation   []TierVariation `json:"tier_variation,omitempty"`
 	VariationIDList []Variation     `json:"variation_id_list"`
 }
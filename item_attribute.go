@@ -7,11 +7,14 @@ type ItemAttributeService interface {
 type ItemAttribute struct {
 	ID        uint64               `json:"attribute_id"`
 	Name      string               `json:"attribute_name"`
-	Mandatory bool                 `json:"is_mandatory"`
+	Mandatory FlexBool             `json:"is_mandatory"`
 	Type      string               `json:"attribute_type"`
 	InputType string               `json:"input_type"`
 	Options   []string             `json:"options"`
 	Values    []ItemAttributeValue `json:"values"`
+
+	// Raw is only set with WithRawFields.
+	Raw *RawFields `json:"-"`
 }
 
 type ItemAttributeValue struct {
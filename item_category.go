@@ -30,8 +30,11 @@ type ItemCategory struct {
 	ID               uint64            `json:"category_id"`
 	ParentID         uint64            `json:"parent_id"`
 	Name             string            `json:"category_name"`
-	HasChildren      bool              `json:"has_children"`
+	HasChildren      FlexBool          `json:"has_children"`
 	DaysToShipLimits *DaysToShipLimits `json:"days_to_ship_limits"`
+
+	// Raw is only set with WithRawFields.
+	Raw *RawFields `json:"-"`
 }
 
 type DaysToShipLimits struct {
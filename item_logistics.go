@@ -0,0 +1,191 @@
+package goshopee
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// updateLogisticsConcurrency is the number of items UpdateLogisticsAll
+// updates at once.
+const updateLogisticsConcurrency = 4
+
+// ItemLogistic is the setting of one logistics channel of an item.
+type ItemLogistic struct {
+	LogisticID  uint64  `json:"logistic_id"`
+	Enabled     bool    `json:"enabled"`
+	ShippingFee Decimal `json:"shipping_fee"` // for CUSTOM_PRICE channels
+	SizeID      uint64  `json:"size_id"`      // for SIZE_SELECTION channels
+	IsFree      bool    `json:"is_free"`
+}
+
+// ItemLogisticsResult is the outcome of UpdateLogisticsAll for one item.
+type ItemLogisticsResult struct {
+	ItemID uint64
+
+	// Changed is false for items already set up as requested, they are
+	// not updated.
+	Changed bool
+
+	Err error
+}
+
+// mergeLogistics applies updates to the logistics of item and returns the
+// resulting channels, and whether any of them changed.
+func mergeLogistics(item *Item, updates []ItemLogistic) ([]ItemLogistic, bool) {
+	if item == nil {
+		item = &Item{}
+	}
+	byID := make(map[uint64]ItemLogistic, len(updates))
+	for _, u := range updates {
+		byID[u.LogisticID] = u
+	}
+	changed := false
+	merged := make([]ItemLogistic, 0, len(item.Logistics)+len(updates))
+	for _, l := range item.Logistics {
+		current := ItemLogistic{
+			LogisticID:  l.ID,
+			Enabled:     bool(l.Enabled),
+			ShippingFee: l.ShippingFee,
+			SizeID:      l.SizeID,
+			IsFree:      bool(l.IsFree),
+		}
+		if u, ok := byID[l.ID]; ok {
+			delete(byID, l.ID)
+			if u.Enabled != current.Enabled || !u.ShippingFee.Equal(current.ShippingFee) ||
+				u.SizeID != current.SizeID || u.IsFree != current.IsFree {
+				changed = true
+			}
+			current = u
+		}
+		merged = append(merged, current)
+	}
+	// channels the item did not list yet, in the order given; disabling
+	// them changes nothing
+	for _, u := range updates {
+		if added, ok := byID[u.LogisticID]; ok {
+			delete(byID, u.LogisticID)
+			if added.Enabled {
+				merged = append(merged, added)
+				changed = true
+			}
+		}
+	}
+	return merged, changed
+}
+
+// withLogistics returns item as an ItemOper with its logistics set to
+// logistics, for validation.
+func withLogistics(item *Item, logistics []ItemLogistic) *ItemOper {
+	oper := &ItemOper{}
+	if item != nil {
+		oper.ItemBase = item.ItemBase
+	}
+	oper.Logistics = make([]Logistic, len(logistics))
+	for i, l := range logistics {
+		oper.Logistics[i] = Logistic{
+			ID:          l.LogisticID,
+			Enabled:     FlexBool(l.Enabled),
+			ShippingFee: l.ShippingFee,
+			SizeID:      l.SizeID,
+			IsFree:      FlexBool(l.IsFree),
+		}
+	}
+	return oper
+}
+
+// UpdateLogistics sets the logistics channels of an item. Only the channels
+// in logistics are changed, or added when the item did not list them; the
+// others keep their settings. Nothing is sent when the item is already set
+// up as requested. Like UpdateLogisticsAll, the resulting logistics are
+// checked with ValidateItemLogistics against the shop channels first. It
+// returns the updated item.
+func (s *ItemServiceOp) UpdateLogistics(ctx context.Context, sid, itemid uint64, logistics []ItemLogistic) (*Item, error) {
+	item, err := s.Get(sid, itemid)
+	if err != nil {
+		return nil, err
+	}
+	merged, changed := mergeLogistics(item, logistics)
+	if !changed {
+		return item, nil
+	}
+	channels, err := s.client.Logistic.List(sid)
+	if err != nil {
+		return nil, fmt.Errorf("list logistics channels: %w", err)
+	}
+	if err := ValidateItemLogistics(withLogistics(item, merged), channels); err != nil {
+		return nil, err
+	}
+	return s.postLogistics(ctx, sid, itemid, merged)
+}
+
+func (s *ItemServiceOp) postLogistics(ctx context.Context, sid, itemid uint64, logistics []ItemLogistic) (*Item, error) {
+	params := map[string]interface{}{
+		"item_id":   itemid,
+		"logistics": logistics,
+	}
+	resource := new(ItemOperResponse)
+	err := s.client.CallShop(ctx, sid, "/item/update", params, resource)
+	return resource.Item, err
+}
+
+// UpdateLogisticsAll applies change, e.g. enabling a new channel or
+// disabling a discontinued one, to every item of the shop matching options.
+// The resulting logistics of each item are checked with
+// ValidateItemLogistics against the shop channels first, invalid items are
+// not updated. It returns a result per item; an error is only returned when
+// the items or channels cannot be listed, or ctx is done.
+func (s *ItemServiceOp) UpdateLogisticsAll(ctx context.Context, sid uint64, change ItemLogistic, options *ItemListOptions) ([]ItemLogisticsResult, error) {
+	channels, err := s.client.Logistic.List(sid)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.List(ctx, sid, options)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ItemLogisticsResult, len(items))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, updateLogisticsConcurrency)
+	for i := range items {
+		results[i].ItemID = items[i].ItemID
+		if ctx.Err() != nil {
+			results[i].Err = ctx.Err()
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(r *ItemLogisticsResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r.Changed, r.Err = s.updateItemLogistics(ctx, sid, r.ItemID, change, channels)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results, ctx.Err()
+}
+
+// updateItemLogistics applies change to one item for UpdateLogisticsAll.
+func (s *ItemServiceOp) updateItemLogistics(ctx context.Context, sid, itemid uint64, change ItemLogistic, channels []Logistic) (bool, error) {
+	item, err := s.Get(sid, itemid)
+	if err != nil {
+		return false, err
+	}
+	merged, changed := mergeLogistics(item, []ItemLogistic{change})
+	if !changed {
+		return false, nil
+	}
+	if err := ValidateItemLogistics(withLogistics(item, merged), channels); err != nil {
+		return false, err
+	}
+	if _, err := s.postLogistics(ctx, sid, itemid, merged); err != nil {
+		return false, err
+	}
+	return true, nil
+}
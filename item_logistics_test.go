@@ -0,0 +1,68 @@
+package goshopee
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeLogistics(t *testing.T) {
+	fee := MustParseDecimal("2.50")
+	item := &Item{ItemBase: ItemBase{Logistics: []Logistic{
+		{ID: 1, Enabled: true},
+		{ID: 2, Enabled: true, ShippingFee: fee},
+		{ID: 3, Enabled: false},
+	}}}
+	current := []ItemLogistic{
+		{LogisticID: 1, Enabled: true},
+		{LogisticID: 2, Enabled: true, ShippingFee: fee},
+		{LogisticID: 3, Enabled: false},
+	}
+	with := func(i int, l ItemLogistic) []ItemLogistic {
+		merged := append([]ItemLogistic(nil), current...)
+		merged[i] = l
+		return merged
+	}
+	cases := []struct {
+		name        string
+		item        *Item
+		updates     []ItemLogistic
+		want        []ItemLogistic
+		wantChanged bool
+	}{
+		{name: "no update", item: item, want: current},
+		{name: "same settings", item: item, updates: []ItemLogistic{{LogisticID: 1, Enabled: true}}, want: current},
+		{name: "same fee at another scale", item: item, updates: []ItemLogistic{{LogisticID: 2, Enabled: true, ShippingFee: MustParseDecimal("2.5")}}, want: with(1, ItemLogistic{LogisticID: 2, Enabled: true, ShippingFee: MustParseDecimal("2.5")})},
+		{name: "disable", item: item, updates: []ItemLogistic{{LogisticID: 1}}, want: with(0, ItemLogistic{LogisticID: 1}), wantChanged: true},
+		{name: "enable", item: item, updates: []ItemLogistic{{LogisticID: 3, Enabled: true}}, want: with(2, ItemLogistic{LogisticID: 3, Enabled: true}), wantChanged: true},
+		{name: "change fee", item: item, updates: []ItemLogistic{{LogisticID: 2, Enabled: true, ShippingFee: MustParseDecimal("3")}}, want: with(1, ItemLogistic{LogisticID: 2, Enabled: true, ShippingFee: MustParseDecimal("3")}), wantChanged: true},
+		{name: "free", item: item, updates: []ItemLogistic{{LogisticID: 2, Enabled: true, ShippingFee: fee, IsFree: true}}, want: with(1, ItemLogistic{LogisticID: 2, Enabled: true, ShippingFee: fee, IsFree: true}), wantChanged: true},
+		{name: "add channel", item: item, updates: []ItemLogistic{{LogisticID: 4, Enabled: true, SizeID: 7}}, want: append(current, ItemLogistic{LogisticID: 4, Enabled: true, SizeID: 7}), wantChanged: true},
+		{name: "disable absent channel", item: item, updates: []ItemLogistic{{LogisticID: 4}}, want: current},
+		{name: "disable absent and enable present", item: item, updates: []ItemLogistic{{LogisticID: 4}, {LogisticID: 3, Enabled: true}}, want: with(2, ItemLogistic{LogisticID: 3, Enabled: true}), wantChanged: true},
+		{name: "add channels in order", item: item, updates: []ItemLogistic{{LogisticID: 6, Enabled: true}, {LogisticID: 5, Enabled: true}}, want: append(current, ItemLogistic{LogisticID: 6, Enabled: true}, ItemLogistic{LogisticID: 5, Enabled: true}), wantChanged: true},
+		{name: "last update of a channel wins", item: item, updates: []ItemLogistic{{LogisticID: 4, Enabled: true}, {LogisticID: 4}}, want: current},
+		{name: "item without logistics", item: &Item{}, updates: []ItemLogistic{{LogisticID: 1, Enabled: true}}, want: []ItemLogistic{{LogisticID: 1, Enabled: true}}, wantChanged: true},
+		{name: "nil item", updates: []ItemLogistic{{LogisticID: 1}}, want: []ItemLogistic{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, changed := mergeLogistics(tc.item, tc.updates)
+			if changed != tc.wantChanged {
+				t.Errorf("changed = %t, want %t", changed, tc.wantChanged)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("merged = %+v, want %+v", got, tc.want)
+			}
+			for i := range got {
+				g, w := got[i], tc.want[i]
+				if !g.ShippingFee.Equal(w.ShippingFee) {
+					t.Errorf("merged[%d] fee = %s, want %s", i, g.ShippingFee, w.ShippingFee)
+				}
+				g.ShippingFee, w.ShippingFee = Decimal{}, Decimal{}
+				if !reflect.DeepEqual(g, w) {
+					t.Errorf("merged[%d] = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package goshopee_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+)
+
+func TestUpdateLogisticsAll(t *testing.T) {
+	cases := []struct {
+		name        string
+		change      goshopee.ItemLogistic
+		wantChanged int
+	}{
+		{name: "disable absent channel", change: goshopee.ItemLogistic{LogisticID: 3}},
+		{name: "disable listed channel", change: goshopee.ItemLogistic{LogisticID: 2}, wantChanged: 1},
+		{name: "enable new channel", change: goshopee.ItemLogistic{LogisticID: 3, Enabled: true}, wantChanged: 3},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			for _, id := range []uint64{1, 2, 3} {
+				srv.AddLogistic(7, goshopee.Logistic{ID: id, Enabled: true})
+			}
+			srv.AddItem(7, goshopee.Item{ItemBase: goshopee.ItemBase{ShopID: 7, Name: "a", Weight: 1, Logistics: []goshopee.Logistic{{ID: 1, Enabled: true}}}})
+			srv.AddItem(7, goshopee.Item{ItemBase: goshopee.ItemBase{ShopID: 7, Name: "b", Weight: 1, Logistics: []goshopee.Logistic{{ID: 1, Enabled: true}, {ID: 2, Enabled: true}}}})
+			srv.AddItem(7, goshopee.Item{ItemBase: goshopee.ItemBase{ShopID: 7, Name: "c", Weight: 1, Logistics: []goshopee.Logistic{{ID: 1, Enabled: true}, {ID: 2}}}})
+
+			results, err := srv.Client().Item.UpdateLogisticsAll(context.Background(), 7, tc.change, nil)
+			if err != nil {
+				t.Fatalf("UpdateLogisticsAll: %v", err)
+			}
+			if len(results) != 3 {
+				t.Fatalf("%d results, want 3", len(results))
+			}
+			changed := 0
+			for _, r := range results {
+				if r.Err != nil {
+					t.Errorf("item %d: %v", r.ItemID, r.Err)
+				}
+				if r.Changed {
+					changed++
+				}
+			}
+			updates := 0
+			for _, r := range srv.Requests() {
+				if r.Path == "/item/update" {
+					updates++
+				}
+			}
+			if changed != tc.wantChanged || updates != tc.wantChanged {
+				t.Errorf("%d items changed in %d updates, want %d", changed, updates, tc.wantChanged)
+			}
+		})
+	}
+}
+
+func TestUpdateLogistics(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cases := []struct {
+		name      string
+		ctx       context.Context
+		logistics []goshopee.ItemLogistic
+		wantErr   bool
+		wantPaths []string
+	}{
+		{
+			name:      "enable channel",
+			logistics: []goshopee.ItemLogistic{{LogisticID: 2, Enabled: true}},
+			wantPaths: []string{"/item/get", "/logistics/channel/get", "/item/update"},
+		},
+		{
+			name:      "unchanged",
+			logistics: []goshopee.ItemLogistic{{LogisticID: 1, Enabled: true}},
+			wantPaths: []string{"/item/get"},
+		},
+		{
+			name:      "disabled channel",
+			logistics: []goshopee.ItemLogistic{{LogisticID: 3, Enabled: true}},
+			wantErr:   true,
+			wantPaths: []string{"/item/get", "/logistics/channel/get"},
+		},
+		{
+			name:      "no channel left",
+			logistics: []goshopee.ItemLogistic{{LogisticID: 1}},
+			wantErr:   true,
+			wantPaths: []string{"/item/get", "/logistics/channel/get"},
+		},
+		{
+			name:      "cancelled",
+			ctx:       cancelled,
+			logistics: []goshopee.ItemLogistic{{LogisticID: 2, Enabled: true}},
+			wantErr:   true,
+			wantPaths: []string{"/item/get", "/logistics/channel/get"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			srv.AddLogistic(7, goshopee.Logistic{ID: 1, Enabled: true})
+			srv.AddLogistic(7, goshopee.Logistic{ID: 2, Enabled: true})
+			srv.AddLogistic(7, goshopee.Logistic{ID: 3, Enabled: false})
+			item := srv.AddItem(7, goshopee.Item{ItemBase: goshopee.ItemBase{ShopID: 7, Name: "a", Weight: 1, Logistics: []goshopee.Logistic{{ID: 1, Enabled: true}}}})
+
+			ctx := tc.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			_, err := srv.Client().Item.UpdateLogistics(ctx, 7, item.ItemID, tc.logistics)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("UpdateLogistics error = %v, want error %t", err, tc.wantErr)
+			}
+			var paths []string
+			for _, r := range srv.Requests() {
+				paths = append(paths, r.Path)
+			}
+			if !reflect.DeepEqual(paths, tc.wantPaths) {
+				t.Errorf("calls = %v, want %v", paths, tc.wantPaths)
+			}
+		})
+	}
+}
@@ -1,20 +1,70 @@
 package goshopee
 
+import "context"
+
 type Logistic struct {
-	ID                   uint64  `json:"logistic_id"`
-	Name                 string  `json:"logistic_name"`
-	Enabled              bool    `json:"enabled"`
-	ShippingFee          float64 `json:"shipping_fee"`
-	SizeID               uint64  `json:"size_id"`
-	IsFree               bool    `json:"is_free"`
-	EstimatedShippingFee float64 `json:"estimated_shipping_fee"`
+	ID                   uint64   `json:"logistic_id"`
+	Name                 string   `json:"logistic_name"`
+	Enabled              FlexBool `json:"enabled"`
+	ShippingFee          Decimal  `json:"shipping_fee"`
+	SizeID               uint64   `json:"size_id"`
+	IsFree               FlexBool `json:"is_free"`
+	EstimatedShippingFee Decimal  `json:"estimated_shipping_fee"`
+
+	// The fields below describe the channels of the shop, from List.
+	HasCOD           FlexBool              `json:"has_cod,omitempty"`
+	FeeType          string                `json:"fee_type,omitempty"`
+	Sizes            []LogisticSize        `json:"sizes,omitempty"`
+	WeightLimits     *LogisticWeightLimits `json:"weight_limits,omitempty"`
+	ItemMaxDimension *LogisticDimension    `json:"item_max_dimension,omitempty"`
+
+	// Raw is only set with WithRawFields.
+	Raw *RawFields `json:"-"`
+}
+
+// Fee types of a logistics channel, telling what an item listed with the
+// channel must provide.
+const (
+	LogisticFeeSizeSelection = "SIZE_SELECTION"      // a SizeID among the channel Sizes
+	LogisticFeeSizeInput     = "SIZE_INPUT"          // the package dimensions
+	LogisticFeeFixedDefault  = "FIXED_DEFAULT_PRICE" // nothing
+	LogisticFeeCustomPrice   = "CUSTOM_PRICE"        // a ShippingFee
+)
+
+// LogisticSize is a parcel size offered by a channel with the
+// SIZE_SELECTION fee type.
+type LogisticSize struct {
+	SizeID       uint64  `json:"size_id"`
+	Name         string  `json:"name"`
+	DefaultPrice Decimal `json:"default_price"`
+}
+
+// LogisticWeightLimits bounds the weight of the items shipped with a
+// channel, in kg.
+type LogisticWeightLimits struct {
+	ItemMaxWeight float64 `json:"item_max_weight"`
+	ItemMinWeight float64 `json:"item_min_weight"`
+}
+
+// LogisticDimension bounds the package dimensions of the items shipped with
+// a channel. A zero bound is not enforced.
+type LogisticDimension struct {
+	Height       float64 `json:"height"`
+	Width        float64 `json:"width"`
+	Length       float64 `json:"length"`
+	Unit         string  `json:"unit"`
+	DimensionSum float64 `json:"dimension_sum"`
 }
 
 type LogisticService interface {
-	Init(uint64, string, map[string]interface{}) (string, error)
-	GetParameterForInit(sid uint64, ordersn string) (*map[string]interface{}, error)
+	Init(sid uint64, ordersn string, req LogisticInitRequest, params *GetParameterForInitResponse) (*LogisticInitResponse, error)
+	GetParameterForInit(sid uint64, ordersn string) (*GetParameterForInitResponse, error)
 	GetLogisticInfo(sid uint64, ordersn string) (*GetLogisticInfoResponse, error)
 	List(uint64) ([]Logistic, error)
+	GetAirwayBill(sid uint64, ordersn []string, batch bool) (*AirwayBillResponse, error)
+	DownloadAirwayBills(ctx context.Context, sid uint64, ordersn []string, merge bool, w AirwayBillWriter) (*AirwayBillDownload, error)
+	GetTrackingInfo(sid uint64, ordersn string) (*TrackingInfo, error)
+	WaitTrackingNumber(ctx context.Context, sid uint64, ordersn string, opts *TrackingPollOptions) (string, error)
 }
 
 // LogisticServiceOp handles communication with the logistics related methods of
@@ -45,35 +95,44 @@ func (s *LogisticServiceOp) List(sid uint64) ([]Logistic, error) {
 }
 
 // Init https://open.shopee.com/documents?module=3&type=1&id=389
-func (s *LogisticServiceOp) Init(sid uint64, ordersn string, params map[string]interface{}) (string, error) {
-	path := "/logistics/init"
-	wrappedData := map[string]interface{}{
-		"ordersn": ordersn,
-		"shopid":  sid,
+//
+// req must set exactly one of the pickup, dropoff or non-integrated
+// parameters, it is checked with LogisticInitRequest.Validate against
+// params, the fields GetParameterForInit requires for the order, before
+// anything is sent. params is fetched when nil.
+func (s *LogisticServiceOp) Init(sid uint64, ordersn string, req LogisticInitRequest, params *GetParameterForInitResponse) (*LogisticInitResponse, error) {
+	if _, err := req.Method(); err != nil {
+		return nil, err
+	}
+	if params == nil {
+		var err error
+		if params, err = s.GetParameterForInit(sid, ordersn); err != nil {
+			return nil, err
+		}
 	}
-	for k, v := range params {
-		wrappedData[k] = v
+	if err := req.Validate(params); err != nil {
+		return nil, err
 	}
+	path := "/logistics/init"
+	wrappedData, err := ToMapData(req)
+	if err != nil {
+		return nil, err
+	}
+	wrappedData["ordersn"] = ordersn
+	wrappedData["shopid"] = sid
 	resource := new(LogisticInitResponse)
-	err := s.client.Post(path, wrappedData, resource)
-	return resource.TrackingNumber, err
+	err = s.client.Post(path, wrappedData, resource)
+	return resource, err
 }
 
-// type GetParameterForInitResponse struct {
-// 	Pickup        []string `json:"pickup"`
-// 	Dropoff       []string `json:"dropoff"`
-// 	NonIntegrated []string `json:"non_integrated"`
-// 	RequestID     string   `json:"request_id"`
-// }
-
 // GetParameterForInit https://open.shopee.com/documents?module=3&type=1&id=386
-func (s *LogisticServiceOp) GetParameterForInit(sid uint64, ordersn string) (*map[string]interface{}, error) {
+func (s *LogisticServiceOp) GetParameterForInit(sid uint64, ordersn string) (*GetParameterForInitResponse, error) {
 	path := "/logistics/init_parameter/get"
 	wrappedData := map[string]interface{}{
 		"ordersn": ordersn,
 		"shopid":  sid,
 	}
-	resource := new(map[string]interface{})
+	resource := new(GetParameterForInitResponse)
 	err := s.client.Post(path, wrappedData, resource)
 	return resource, err
 }
@@ -0,0 +1,288 @@
+package goshopee
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxAirwayBillOrders is the most orders GetAirwayBill accepts in one call.
+const maxAirwayBillOrders = 50
+
+// AirwayBill is the URL of the airway bill of one order.
+type AirwayBill struct {
+	OrderSN    string `json:"ordersn"`
+	AirwayBill string `json:"airway_bill"`
+}
+
+// AirwayBillError is an order GetAirwayBill could not return an airway bill
+// for, e.g. because its logistics are not initialized yet.
+type AirwayBillError struct {
+	OrderSN          string     `json:"ordersn"`
+	ErrorCode        FlexString `json:"error_code"`
+	ErrorDescription string     `json:"error_description"`
+}
+
+// UnmarshalJSON decodes e from an object, or from a bare ordersn as the
+// batch mode reports them.
+func (e *AirwayBillError) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		*e = AirwayBillError{}
+		return json.Unmarshal(data, &e.OrderSN)
+	}
+	type plain AirwayBillError
+	return json.Unmarshal(data, (*plain)(e))
+}
+
+// AirwayBillResult is the result of GetAirwayBill in normal mode, one
+// airway bill per order.
+type AirwayBillResult struct {
+	TotalCount  FlexInt           `json:"total_count"`
+	AirwayBills []AirwayBill      `json:"airway_bills"`
+	Errors      []AirwayBillError `json:"errors"`
+}
+
+// AirwayBillBatchResult is the result of GetAirwayBill in batch mode, where
+// Shopee merges the airway bills of the orders into documents holding
+// several of them.
+type AirwayBillBatchResult struct {
+	TotalCount  FlexInt           `json:"total_count"`
+	AirwayBills []string          `json:"airway_bills"`
+	Errors      []AirwayBillError `json:"errors"`
+}
+
+type AirwayBillResponse struct {
+	Result      *AirwayBillResult      `json:"result,omitempty"`
+	BatchResult *AirwayBillBatchResult `json:"batch_result,omitempty"`
+	RequestID   string                 `json:"request_id"`
+}
+
+// GetAirwayBill wraps shopee.logistics.GetAirwayBill, it returns the URLs of
+// the airway bills of up to 50 orders. With batch, the airway bills are
+// merged into documents holding several orders, in BatchResult; otherwise
+// there is one document per order, in Result.
+func (s *LogisticServiceOp) GetAirwayBill(sid uint64, ordersn []string, batch bool) (*AirwayBillResponse, error) {
+	if len(ordersn) == 0 {
+		return nil, errEmptyOrderSN
+	}
+	if len(ordersn) > maxAirwayBillOrders {
+		return nil, fmt.Errorf("at most %d orders per airway bill request, got %d", maxAirwayBillOrders, len(ordersn))
+	}
+	path := "/logistics/airway_bill/get_mass"
+	wrappedData := map[string]interface{}{
+		"shopid":       sid,
+		"ordersn_list": ordersn,
+		"is_batch":     batch,
+	}
+	resource := new(AirwayBillResponse)
+	err := s.client.Post(path, wrappedData, resource)
+	return resource, err
+}
+
+// AirwayBillWriter returns the writer the PDF document holding the airway
+// bills of ordersn is streamed to. It is called once per order, or once for
+// the merged document when merging.
+type AirwayBillWriter func(ordersn []string) (io.Writer, error)
+
+var errMultipleAirwayBills = errors.New("airway bills span several documents, merge them or write each document separately")
+
+// AirwayBillTo streams a single document to w: one order, or up to 50
+// merged orders. It fails on a second document as concatenated PDFs do not
+// make a valid one.
+func AirwayBillTo(w io.Writer) AirwayBillWriter {
+	used := false
+	return func(ordersn []string) (io.Writer, error) {
+		if used {
+			return nil, errMultipleAirwayBills
+		}
+		used = true
+		return w, nil
+	}
+}
+
+// AirwayBillDocument is a document written by DownloadAirwayBills.
+type AirwayBillDocument struct {
+	OrderSN []string
+	URL     string
+	Size    int64
+}
+
+// AirwayBillFailure is an order DownloadAirwayBills got no airway bill for.
+type AirwayBillFailure struct {
+	OrderSN string
+	Error   string
+}
+
+// AirwayBillDownload is the outcome of DownloadAirwayBills.
+type AirwayBillDownload struct {
+	Documents []AirwayBillDocument
+	Failures  []AirwayBillFailure
+}
+
+// DownloadAirwayBills gets the airway bills of ordersn, in calls of up to
+// 50 orders, and streams the PDF documents to the writers returned by w.
+// With merge, Shopee's batch mode is used to get a single document holding
+// all the orders, ready to print, so at most 50 orders can be merged;
+// otherwise each order gets its own document.
+//
+// Orders Shopee has no airway bill for, or returns nothing for, and
+// documents that fail to download before any of their bytes were written,
+// are reported in Failures
+// rather than as an error. An error is returned when a call to Shopee, or w
+// or one of its writers, fails, when a download fails after part of the
+// document was written, or when ctx is done.
+func (s *LogisticServiceOp) DownloadAirwayBills(ctx context.Context, sid uint64, ordersn []string, merge bool, w AirwayBillWriter) (*AirwayBillDownload, error) {
+	if merge && len(ordersn) > maxAirwayBillOrders {
+		return nil, fmt.Errorf("at most %d orders can be merged into one airway bill document, got %d", maxAirwayBillOrders, len(ordersn))
+	}
+	download := &AirwayBillDownload{}
+	for start := 0; start < len(ordersn); start += maxAirwayBillOrders {
+		end := start + maxAirwayBillOrders
+		if end > len(ordersn) {
+			end = len(ordersn)
+		}
+		chunk := ordersn[start:end]
+		if err := ctx.Err(); err != nil {
+			return download, err
+		}
+		resp, err := s.GetAirwayBill(sid, chunk, merge)
+		if err != nil {
+			return download, err
+		}
+
+		var docs []AirwayBillDocument
+		var failed []AirwayBillError
+		switch {
+		case merge && resp.BatchResult != nil:
+			if len(resp.BatchResult.AirwayBills) > 1 {
+				return download, fmt.Errorf("airway bills were merged into %d documents rather than one", len(resp.BatchResult.AirwayBills))
+			}
+			failed = resp.BatchResult.Errors
+			covered := withoutFailed(chunk, failed)
+			for _, url := range resp.BatchResult.AirwayBills {
+				docs = append(docs, AirwayBillDocument{OrderSN: covered, URL: url})
+			}
+		case resp.Result != nil:
+			failed = resp.Result.Errors
+			for _, bill := range resp.Result.AirwayBills {
+				docs = append(docs, AirwayBillDocument{OrderSN: []string{bill.OrderSN}, URL: bill.AirwayBill})
+			}
+		}
+		accounted := make(map[string]bool, len(chunk))
+		for _, e := range failed {
+			reason := e.ErrorDescription
+			if reason == "" {
+				reason = e.ErrorCode.String()
+			}
+			download.Failures = append(download.Failures, AirwayBillFailure{OrderSN: e.OrderSN, Error: reason})
+			accounted[e.OrderSN] = true
+		}
+		for _, doc := range docs {
+			for _, sn := range doc.OrderSN {
+				accounted[sn] = true
+			}
+		}
+		// orders Shopee returned neither a document nor an error for, e.g.
+		// when the response has no result at all
+		for _, sn := range chunk {
+			if !accounted[sn] {
+				accounted[sn] = true
+				download.Failures = append(download.Failures, AirwayBillFailure{OrderSN: sn, Error: "no airway bill returned"})
+			}
+		}
+
+		for _, doc := range docs {
+			out, err := w(doc.OrderSN)
+			if err != nil {
+				return download, err
+			}
+			n, err := s.fetchAirwayBill(ctx, doc.URL, out)
+			if err != nil {
+				var werr writeError
+				if errors.As(err, &werr) {
+					return download, werr.err
+				}
+				if ctx.Err() != nil {
+					return download, ctx.Err()
+				}
+				if n > 0 {
+					// the writer holds a truncated document
+					return download, fmt.Errorf("download airway bill of %s: %w", strings.Join(doc.OrderSN, ", "), err)
+				}
+				for _, sn := range doc.OrderSN {
+					download.Failures = append(download.Failures, AirwayBillFailure{OrderSN: sn, Error: err.Error()})
+				}
+				continue
+			}
+			doc.Size = n
+			download.Documents = append(download.Documents, doc)
+		}
+	}
+	return download, nil
+}
+
+// withoutFailed returns the orders of ordersn not listed in failed.
+func withoutFailed(ordersn []string, failed []AirwayBillError) []string {
+	if len(failed) == 0 {
+		return ordersn
+	}
+	skip := make(map[string]bool, len(failed))
+	for _, e := range failed {
+		skip[e.OrderSN] = true
+	}
+	var covered []string
+	for _, sn := range ordersn {
+		if !skip[sn] {
+			covered = append(covered, sn)
+		}
+	}
+	return covered
+}
+
+// writeError tells a failure to write a document apart from a failure to
+// download it.
+type writeError struct {
+	err error
+}
+
+func (e writeError) Error() string {
+	return e.err.Error()
+}
+
+// errorWriter wraps the errors of w in writeError.
+type errorWriter struct {
+	w io.Writer
+}
+
+func (e errorWriter) Write(p []byte) (int, error) {
+	n, err := e.w.Write(p)
+	if err != nil {
+		err = writeError{err}
+	}
+	return n, err
+}
+
+// fetchAirwayBill streams the document at url to w. The URLs are pre-signed
+// so the request is sent as is, through the client's transport.
+func (s *LogisticServiceOp) fetchAirwayBill(ctx context.Context, url string, w io.Writer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	s.client.log.Debugf("%s: %s", req.Method, url)
+	resp, err := s.client.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("download airway bill: %s", resp.Status)
+	}
+	return io.Copy(errorWriter{w}, resp.Body)
+}
@@ -0,0 +1,226 @@
+package goshopee_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+)
+
+// newAirwayBillServer returns a fake with the shipped orders SN0..SNn-1 and
+// the order NEW, not shipped yet.
+func newAirwayBillServer(t *testing.T, n int, opts ...goshopee.Option) (*goshopeetest.Server, *goshopee.Client) {
+	t.Helper()
+	srv := goshopeetest.NewServer(1, "key")
+	t.Cleanup(srv.Close)
+	c := srv.Client(opts...)
+	pickup := goshopee.LogisticInitRequest{Pickup: &goshopee.LogisticInitPickupParams{AddressID: 1, PickupTimeID: "1"}}
+	for i := 0; i < n; i++ {
+		sn := fmt.Sprintf("SN%d", i)
+		srv.AddOrder(7, goshopee.Order{OrderSN: sn})
+		if _, err := c.Logistic.Init(7, sn, pickup, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv.AddOrder(7, goshopee.Order{OrderSN: "NEW"})
+	return srv, c
+}
+
+func TestDownloadAirwayBills(t *testing.T) {
+	cases := []struct {
+		name         string
+		ordersn      []string
+		merge        bool
+		wantDocs     [][]string
+		wantFailures []string
+	}{
+		{name: "one per order", ordersn: []string{"SN0", "SN1"}, wantDocs: [][]string{{"SN0"}, {"SN1"}}},
+		{name: "merged", ordersn: []string{"SN0", "SN1", "SN2"}, merge: true, wantDocs: [][]string{{"SN0", "SN1", "SN2"}}},
+		{name: "not shipped", ordersn: []string{"SN0", "NEW"}, wantDocs: [][]string{{"SN0"}}, wantFailures: []string{"NEW"}},
+		{name: "merged not shipped", ordersn: []string{"SN0", "NEW", "SN1"}, merge: true, wantDocs: [][]string{{"SN0", "SN1"}}, wantFailures: []string{"NEW"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c := newAirwayBillServer(t, 3)
+			written := map[string]*bytes.Buffer{}
+			w := func(ordersn []string) (io.Writer, error) {
+				buf := &bytes.Buffer{}
+				written[strings.Join(ordersn, ",")] = buf
+				return buf, nil
+			}
+
+			download, err := c.Logistic.DownloadAirwayBills(context.Background(), 7, tc.ordersn, tc.merge, w)
+			if err != nil {
+				t.Fatalf("DownloadAirwayBills: %v", err)
+			}
+			var docs [][]string
+			for _, doc := range download.Documents {
+				docs = append(docs, doc.OrderSN)
+				buf := written[strings.Join(doc.OrderSN, ",")]
+				if buf == nil || int64(buf.Len()) != doc.Size || !bytes.HasPrefix(buf.Bytes(), []byte("%PDF")) {
+					t.Errorf("document of %v: wrote %q, size %d", doc.OrderSN, buf, doc.Size)
+				}
+			}
+			if !reflect.DeepEqual(docs, tc.wantDocs) {
+				t.Errorf("documents = %v, want %v", docs, tc.wantDocs)
+			}
+			var failures []string
+			for _, f := range download.Failures {
+				failures = append(failures, f.OrderSN)
+			}
+			if !reflect.DeepEqual(failures, tc.wantFailures) {
+				t.Errorf("failures = %v, want %v", failures, tc.wantFailures)
+			}
+		})
+	}
+}
+
+func TestDownloadAirwayBillsMergeLimit(t *testing.T) {
+	srv, c := newAirwayBillServer(t, 51)
+	var ordersn []string
+	for i := 0; i < 51; i++ {
+		ordersn = append(ordersn, fmt.Sprintf("SN%d", i))
+	}
+	calls := len(srv.Requests())
+
+	var out bytes.Buffer
+	_, err := c.Logistic.DownloadAirwayBills(context.Background(), 7, ordersn, true, goshopee.AirwayBillTo(&out))
+	if err == nil {
+		t.Fatal("merging 51 orders succeeded")
+	}
+	if out.Len() > 0 || len(srv.Requests()) != calls {
+		t.Errorf("wrote %d bytes in %d calls, want nothing", out.Len(), len(srv.Requests())-calls)
+	}
+}
+
+func TestDownloadAirwayBillsFailedDownload(t *testing.T) {
+	srv, c := newAirwayBillServer(t, 2)
+	srv.Inject(goshopeetest.Fault{Path: "/airway_bill/", Times: 1, Status: http.StatusInternalServerError, Error: "error_server"})
+
+	download, err := c.Logistic.DownloadAirwayBills(context.Background(), 7, []string{"SN0", "SN1"}, false, func([]string) (io.Writer, error) {
+		return io.Discard, nil
+	})
+	if err != nil {
+		t.Fatalf("DownloadAirwayBills: %v", err)
+	}
+	if len(download.Documents) != 1 || len(download.Failures) != 1 || download.Failures[0].OrderSN != "SN0" {
+		t.Errorf("download = %+v, want SN0 failed and SN1 downloaded", download)
+	}
+}
+
+// truncatingTransport cuts the airway bill documents after a few bytes.
+type truncatingTransport struct{}
+
+func (truncatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil || !strings.Contains(req.URL.Path, "/airway_bill/") {
+		return resp, err
+	}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(io.LimitReader(resp.Body, 4), errorReader{}), resp.Body}
+	return resp, nil
+}
+
+type errorReader struct{}
+
+func (errorReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestDownloadAirwayBillsTruncated(t *testing.T) {
+	_, c := newAirwayBillServer(t, 2, goshopee.WithTransport(truncatingTransport{}))
+
+	var out bytes.Buffer
+	download, err := c.Logistic.DownloadAirwayBills(context.Background(), 7, []string{"SN0", "SN1"}, true, goshopee.AirwayBillTo(&out))
+	if err == nil {
+		t.Fatal("truncated download succeeded")
+	}
+	if len(download.Documents) != 0 || len(download.Failures) != 0 {
+		t.Errorf("download = %+v, want no document nor failure", download)
+	}
+}
+
+func TestDownloadAirwayBillsCancelled(t *testing.T) {
+	_, c := newAirwayBillServer(t, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	w := func([]string) (io.Writer, error) {
+		cancel()
+		return io.Discard, nil
+	}
+
+	download, err := c.Logistic.DownloadAirwayBills(ctx, 7, []string{"SN0", "SN1"}, false, w)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if len(download.Failures) != 0 {
+		t.Errorf("failures = %+v, want none", download.Failures)
+	}
+}
+
+func TestDownloadAirwayBillsUnaccounted(t *testing.T) {
+	cases := []struct {
+		name         string
+		merge        bool
+		body         string
+		wantDocs     [][]string
+		wantFailures []string
+	}{
+		{name: "no result", body: `{"request_id": "r1"}`, wantFailures: []string{"SN0", "SN1"}},
+		{name: "merged without batch result", merge: true, body: `{"request_id": "r1"}`, wantFailures: []string{"SN0", "SN1"}},
+		{
+			name:         "merged without document",
+			merge:        true,
+			body:         `{"batch_result": {"total_count": 0, "airway_bills": [], "errors": []}, "request_id": "r1"}`,
+			wantFailures: []string{"SN0", "SN1"},
+		},
+		{
+			name:         "order left out",
+			body:         `{"result": {"total_count": 1, "airway_bills": [{"ordersn": "SN0", "airway_bill": "URL/SN0.pdf"}], "errors": []}, "request_id": "r1"}`,
+			wantDocs:     [][]string{{"SN0"}},
+			wantFailures: []string{"SN1"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var srv *httptest.Server
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, ".pdf") {
+					w.Write([]byte("%PDF-1.4"))
+					return
+				}
+				w.Write([]byte(strings.Replace(tc.body, "URL", srv.URL, -1)))
+			}))
+			defer srv.Close()
+			c := goshopee.NewClient(goshopee.App{PartnerID: 1, PartnerKey: "key", APIURL: srv.URL})
+
+			w := func(ordersn []string) (io.Writer, error) { return io.Discard, nil }
+			download, err := c.Logistic.DownloadAirwayBills(context.Background(), 7, []string{"SN0", "SN1"}, tc.merge, w)
+			if err != nil {
+				t.Fatalf("DownloadAirwayBills: %v", err)
+			}
+			var docs [][]string
+			for _, doc := range download.Documents {
+				docs = append(docs, doc.OrderSN)
+			}
+			if !reflect.DeepEqual(docs, tc.wantDocs) {
+				t.Errorf("documents = %v, want %v", docs, tc.wantDocs)
+			}
+			var failures []string
+			for _, f := range download.Failures {
+				failures = append(failures, f.OrderSN)
+			}
+			if !reflect.DeepEqual(failures, tc.wantFailures) {
+				t.Errorf("failures = %v, want %v", failures, tc.wantFailures)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package goshopee
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LogisticInitMethod is how the parcel of an order gets to the carrier.
+type LogisticInitMethod string
+
+const (
+	// LogisticInitPickup has the carrier collect the parcel at a pickup
+	// address of the shop.
+	LogisticInitPickup LogisticInitMethod = "pickup"
+	// LogisticInitDropoff has the seller bring the parcel to a branch.
+	LogisticInitDropoff LogisticInitMethod = "dropoff"
+	// LogisticInitNonIntegrated is for channels not integrated with Shopee,
+	// the seller ships the parcel and provides the tracking number.
+	LogisticInitNonIntegrated LogisticInitMethod = "non_integrated"
+)
+
+// GetParameterForInitResponse lists, for each method of initializing the
+// logistics of an order, the fields LogisticService.Init requires. A nil
+// list means the method is not available for the order.
+type GetParameterForInitResponse struct {
+	Pickup        []string `json:"pickup"`
+	Dropoff       []string `json:"dropoff"`
+	NonIntegrated []string `json:"non_integrated"`
+	RequestID     string   `json:"request_id"`
+}
+
+// Required returns the fields method requires, and false when the method is
+// not available for the order.
+func (p *GetParameterForInitResponse) Required(method LogisticInitMethod) ([]string, bool) {
+	var fields []string
+	switch method {
+	case LogisticInitPickup:
+		fields = p.Pickup
+	case LogisticInitDropoff:
+		fields = p.Dropoff
+	case LogisticInitNonIntegrated:
+		fields = p.NonIntegrated
+	}
+	return fields, fields != nil
+}
+
+// LogisticInitPickupParams books a pickup of the parcel, AddressID and
+// PickupTimeID come from GetLogisticInfo.
+type LogisticInitPickupParams struct {
+	AddressID    uint64 `json:"address_id,omitempty"`
+	PickupTimeID string `json:"pickup_time_id,omitempty"`
+}
+
+// LogisticInitDropoffParams declares the parcel will be dropped off at a
+// branch returned by GetLogisticInfo.
+type LogisticInitDropoffParams struct {
+	BranchID       uint64 `json:"branch_id,omitempty"`
+	SenderRealName string `json:"sender_real_name,omitempty"`
+	TrackingNo     string `json:"tracking_no,omitempty"`
+}
+
+// LogisticInitNonIntegratedParams provides the tracking number of a parcel
+// shipped with a channel not integrated with Shopee.
+type LogisticInitNonIntegratedParams struct {
+	TrackingNo string `json:"tracking_no,omitempty"`
+}
+
+// LogisticInitRequest are the parameters of LogisticService.Init, exactly
+// one of Pickup, Dropoff and NonIntegrated must be set.
+type LogisticInitRequest struct {
+	Pickup        *LogisticInitPickupParams        `json:"pickup,omitempty"`
+	Dropoff       *LogisticInitDropoffParams       `json:"dropoff,omitempty"`
+	NonIntegrated *LogisticInitNonIntegratedParams `json:"non_integrated,omitempty"`
+}
+
+var errLogisticInitMethod = errors.New("exactly one of pickup, dropoff and non_integrated must be set")
+
+// MissingInitParameterError is returned when a LogisticInitRequest lacks
+// fields GetParameterForInit requires.
+type MissingInitParameterError struct {
+	Method LogisticInitMethod
+	Fields []string
+}
+
+func (e MissingInitParameterError) Error() string {
+	return fmt.Sprintf("%s requires %s", e.Method, strings.Join(e.Fields, ", "))
+}
+
+// Method returns the method r uses, or an error unless exactly one is set.
+func (r LogisticInitRequest) Method() (LogisticInitMethod, error) {
+	var methods []LogisticInitMethod
+	if r.Pickup != nil {
+		methods = append(methods, LogisticInitPickup)
+	}
+	if r.Dropoff != nil {
+		methods = append(methods, LogisticInitDropoff)
+	}
+	if r.NonIntegrated != nil {
+		methods = append(methods, LogisticInitNonIntegrated)
+	}
+	if len(methods) != 1 {
+		return "", errLogisticInitMethod
+	}
+	return methods[0], nil
+}
+
+// Validate checks r sets exactly one method and, when params is not nil,
+// that the method is available and every field it requires is set.
+func (r LogisticInitRequest) Validate(params *GetParameterForInitResponse) error {
+	method, err := r.Method()
+	if err != nil {
+		return err
+	}
+	if params == nil {
+		return nil
+	}
+	required, ok := params.Required(method)
+	if !ok {
+		return fmt.Errorf("%s is not available for this order", method)
+	}
+	set := r.fieldsSet()
+	var missing []string
+	for _, field := range required {
+		if !set[field] {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return MissingInitParameterError{Method: method, Fields: missing}
+	}
+	return nil
+}
+
+// fieldsSet returns the JSON names of the non-empty fields of the method r
+// uses.
+func (r LogisticInitRequest) fieldsSet() map[string]bool {
+	switch {
+	case r.Pickup != nil:
+		return map[string]bool{
+			"address_id":     r.Pickup.AddressID != 0,
+			"pickup_time_id": r.Pickup.PickupTimeID != "",
+		}
+	case r.Dropoff != nil:
+		return map[string]bool{
+			"branch_id":        r.Dropoff.BranchID != 0,
+			"sender_real_name": r.Dropoff.SenderRealName != "",
+			"tracking_no":      r.Dropoff.TrackingNo != "",
+		}
+	case r.NonIntegrated != nil:
+		return map[string]bool{
+			"tracking_no": r.NonIntegrated.TrackingNo != "",
+		}
+	}
+	return nil
+}
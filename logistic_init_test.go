@@ -0,0 +1,75 @@
+package goshopee_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+)
+
+func TestLogisticInit(t *testing.T) {
+	pickup := goshopee.LogisticInitRequest{Pickup: &goshopee.LogisticInitPickupParams{AddressID: 1, PickupTimeID: "t1"}}
+	cases := []struct {
+		name string
+		req  goshopee.LogisticInitRequest
+		// params given to Init, nil to let it fetch them
+		params      *goshopee.GetParameterForInitResponse
+		wantMissing []string
+		wantErr     bool
+		wantPaths   []string
+	}{
+		{
+			name:      "params fetched",
+			req:       pickup,
+			wantPaths: []string{"/logistics/init_parameter/get", "/logistics/init"},
+		},
+		{
+			name:      "params given",
+			req:       pickup,
+			params:    &goshopee.GetParameterForInitResponse{Pickup: []string{"address_id", "pickup_time_id"}},
+			wantPaths: []string{"/logistics/init"},
+		},
+		{
+			name:        "missing field",
+			req:         goshopee.LogisticInitRequest{Pickup: &goshopee.LogisticInitPickupParams{AddressID: 1}},
+			wantMissing: []string{"pickup_time_id"},
+			wantErr:     true,
+			wantPaths:   []string{"/logistics/init_parameter/get"},
+		},
+		{
+			name:      "method not available",
+			req:       goshopee.LogisticInitRequest{Dropoff: &goshopee.LogisticInitDropoffParams{BranchID: 1}},
+			wantErr:   true,
+			wantPaths: []string{"/logistics/init_parameter/get"},
+		},
+		{
+			name:    "no method",
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			srv.AddOrder(7, goshopee.Order{OrderSN: "SN1"})
+
+			_, err := srv.Client().Logistic.Init(7, "SN1", tc.req, tc.params)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Init error = %v, want error %t", err, tc.wantErr)
+			}
+			var missing goshopee.MissingInitParameterError
+			if errors.As(err, &missing) != (tc.wantMissing != nil) || !reflect.DeepEqual(missing.Fields, tc.wantMissing) {
+				t.Errorf("Init error = %v, want missing %v", err, tc.wantMissing)
+			}
+			var paths []string
+			for _, r := range srv.Requests() {
+				paths = append(paths, r.Path)
+			}
+			if !reflect.DeepEqual(paths, tc.wantPaths) {
+				t.Errorf("calls = %v, want %v", paths, tc.wantPaths)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package goshopee
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TrackingEvent is a step of the journey of a parcel.
+type TrackingEvent struct {
+	CTime       int64      `json:"ctime"`
+	Status      FlexString `json:"status"`
+	Description string     `json:"description"`
+}
+
+// Time returns CTime as a time.Time.
+func (e TrackingEvent) Time() time.Time {
+	return unixToTime(e.CTime)
+}
+
+// Logistics statuses of TrackingInfo.
+const (
+	LogisticsStatusNotStarted     = "LOGISTICS_NOT_START"
+	LogisticsStatusReady          = "LOGISTICS_READY"
+	LogisticsStatusRequestCreated = "LOGISTICS_REQUEST_CREATED" // initialized, waiting for the carrier
+	LogisticsStatusPickupDone     = "LOGISTICS_PICKUP_DONE"
+	LogisticsStatusPickupRetry    = "LOGISTICS_PICKUP_RETRY"
+	LogisticsStatusPickupFailed   = "LOGISTICS_PICKUP_FAILED"
+	LogisticsStatusDeliveryDone   = "LOGISTICS_DELIVERY_DONE"
+	LogisticsStatusDeliveryFailed = "LOGISTICS_DELIVERY_FAILED"
+	LogisticsStatusRequestCancel  = "LOGISTICS_REQUEST_CANCELED"
+	LogisticsStatusCODRejected    = "LOGISTICS_COD_REJECTED"
+	LogisticsStatusInvalid        = "LOGISTICS_INVALID"
+	LogisticsStatusLost           = "LOGISTICS_LOST"
+)
+
+// TrackingInfo is the tracking history of the parcel of an order, oldest
+// event first.
+type TrackingInfo struct {
+	OrderSN         string          `json:"ordersn"`
+	TrackingNumber  string          `json:"tracking_number"`
+	LogisticsStatus string          `json:"logistics_status"`
+	Events          []TrackingEvent `json:"tracking_info"`
+	RequestID       string          `json:"request_id"`
+}
+
+// Latest returns the most recent event, and false when there is none yet.
+func (t *TrackingInfo) Latest() (TrackingEvent, bool) {
+	var latest TrackingEvent
+	for _, e := range t.Events {
+		if e.CTime >= latest.CTime {
+			latest = e
+		}
+	}
+	return latest, len(t.Events) > 0
+}
+
+// GetTrackingInfo wraps shopee.logistics.GetTrackingInfo, it returns the
+// tracking history of the parcel of an order.
+func (s *LogisticServiceOp) GetTrackingInfo(sid uint64, ordersn string) (*TrackingInfo, error) {
+	if ordersn == "" {
+		return nil, errEmptyOrderSN
+	}
+	path := "/logistics/tracking"
+	wrappedData := map[string]interface{}{
+		"ordersn": ordersn,
+		"shopid":  sid,
+	}
+	resource := new(TrackingInfo)
+	err := s.client.Post(path, wrappedData, resource)
+	if err == nil && resource.OrderSN == "" {
+		resource.OrderSN = ordersn
+	}
+	return resource, err
+}
+
+// TrackingPollOptions tunes WaitTrackingNumber. The zero value waits 2s
+// before polling again, doubling the wait up to 1m.
+type TrackingPollOptions struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Multiplier  float64
+}
+
+func (o *TrackingPollOptions) withDefaults() TrackingPollOptions {
+	opts := TrackingPollOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = time.Minute
+	}
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = opts.Interval
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = 2
+	}
+	return opts
+}
+
+// WaitTrackingNumber polls the order until its tracking number is assigned
+// and returns it, for channels where Init returns before the carrier
+// assigned one. It gives up when ctx is done or the order gets cancelled.
+func (s *LogisticServiceOp) WaitTrackingNumber(ctx context.Context, sid uint64, ordersn string, opts *TrackingPollOptions) (string, error) {
+	if ordersn == "" {
+		return "", errEmptyOrderSN
+	}
+	o := opts.withDefaults()
+	wait := o.Interval
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		order, err := s.client.Order.Get(sid, ordersn)
+		if err != nil {
+			return "", err
+		}
+		if order.TrackingNo != "" {
+			return order.TrackingNo, nil
+		}
+		switch order.Status {
+		case OrderStatusInCancel, OrderStatusCancelled:
+			return "", fmt.Errorf("order %s is %s, it will not get a tracking number", ordersn, order.Status)
+		}
+
+		s.client.log.Debugf("no tracking number for order %s yet, polling again in %s", ordersn, wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			return "", err
+		}
+		wait = time.Duration(float64(wait) * o.Multiplier)
+		if wait > o.MaxInterval {
+			wait = o.MaxInterval
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package goshopee_test
+
+import (
+	"testing"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+)
+
+func TestGetTrackingInfo(t *testing.T) {
+	cases := []struct {
+		name        string
+		description string
+	}{
+		{"plain", "Parcel picked up"},
+		{"error in description", "address error, retrying delivery"},
+		{"errors in description", "sorting errors, parcel rerouted"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			srv.AddOrder(7, goshopee.Order{OrderSN: "SN1", TrackingNo: "TN1"})
+			srv.SetTrackingEvents(7, "SN1",
+				goshopee.TrackingEvent{CTime: 100, Status: "PICKED_UP", Description: "Shipping label created"},
+				goshopee.TrackingEvent{CTime: 200, Status: "DELIVERY_FAILED", Description: tc.description},
+			)
+
+			info, err := srv.Client().Logistic.GetTrackingInfo(7, "SN1")
+			if err != nil {
+				t.Fatalf("GetTrackingInfo: %v", err)
+			}
+			latest, ok := info.Latest()
+			if !ok || latest.Description != tc.description {
+				t.Errorf("Latest = %+v, %t, want description %q", latest, ok, tc.description)
+			}
+		})
+	}
+}
+
+func TestGetTrackingInfoError(t *testing.T) {
+	srv := goshopeetest.NewServer(1, "key")
+	defer srv.Close()
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN1", TrackingNo: "TN1"})
+	srv.InjectError("/logistics/tracking", 1, "error_param", "no tracking info")
+
+	_, err := srv.Client().Logistic.GetTrackingInfo(7, "SN1")
+	if _, ok := err.(goshopee.ResponseError); !ok {
+		t.Fatalf("GetTrackingInfo error = %v, want a ResponseError", err)
+	}
+}
@@ -0,0 +1,163 @@
+package goshopee
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError is a problem with one field of a request, e.g.
+// logistics[1].size_id.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors are the problems found validating a request.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidateItemLogistics checks the logistics of item against channels, the
+// logistics channels of the shop from LogisticService.List, before the item
+// is created or updated: every channel enabled for the item must be enabled
+// for the shop, provide what the fee type of the channel requires, and the
+// item weight and package dimensions must fit the channel limits. It returns
+// ValidationErrors, or nil when the item is fine.
+func ValidateItemLogistics(item *ItemOper, channels []Logistic) error {
+	return validateItemLogistics(item, channels, false)
+}
+
+// validateItemLogistics is ValidateItemLogistics. When partial, item is an
+// update: a zero weight or package dimension is left unchanged by Shopee, so
+// it is not checked.
+func validateItemLogistics(item *ItemOper, channels []Logistic, partial bool) error {
+	byID := make(map[uint64]*Logistic, len(channels))
+	for i := range channels {
+		byID[channels[i].ID] = &channels[i]
+	}
+
+	var errs ValidationErrors
+	fail := func(field, format string, v ...interface{}) {
+		errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf(format, v...)})
+	}
+
+	enabled := 0
+	seen := map[uint64]bool{}
+	var needDimensions []string
+	for i, l := range item.Logistics {
+		field := fmt.Sprintf("logistics[%d]", i)
+		if seen[l.ID] {
+			fail(field+".logistic_id", "channel %d is listed twice", l.ID)
+			continue
+		}
+		seen[l.ID] = true
+		if !l.Enabled {
+			continue
+		}
+		enabled++
+
+		channel, ok := byID[l.ID]
+		if !ok {
+			fail(field+".logistic_id", "channel %d is not available to the shop", l.ID)
+			continue
+		}
+		if !channel.Enabled {
+			fail(field+".logistic_id", "channel %s is disabled for the shop", channelName(channel))
+			continue
+		}
+
+		switch channel.FeeType {
+		case LogisticFeeSizeSelection:
+			if !hasSize(channel, l.SizeID) {
+				fail(field+".size_id", "channel %s requires one of its sizes, got %d", channelName(channel), l.SizeID)
+			}
+		case LogisticFeeSizeInput:
+			if !partial && (item.PackageLength <= 0 || item.PackageWidth <= 0 || item.PackageHeight <= 0) {
+				needDimensions = append(needDimensions, channelName(channel))
+			}
+		case LogisticFeeCustomPrice:
+			if !l.IsFree && l.ShippingFee.Sign() <= 0 {
+				fail(field+".shipping_fee", "channel %s requires a shipping fee", channelName(channel))
+			}
+		}
+		if l.ShippingFee.Sign() < 0 {
+			fail(field+".shipping_fee", "must not be negative")
+		}
+
+		if limits := channel.WeightLimits; limits != nil {
+			if limits.ItemMaxWeight > 0 && item.Weight > limits.ItemMaxWeight {
+				fail("weight", "%g kg exceeds the %g kg limit of channel %s", item.Weight, limits.ItemMaxWeight, channelName(channel))
+			}
+			if item.Weight < limits.ItemMinWeight && !(partial && item.Weight == 0) {
+				fail("weight", "%g kg is below the %g kg minimum of channel %s", item.Weight, limits.ItemMinWeight, channelName(channel))
+			}
+		}
+		if max := channel.ItemMaxDimension; max != nil {
+			checkDimension := func(field string, value, limit float64) {
+				if limit > 0 && value > limit {
+					fail(field, "%g exceeds the %g %s limit of channel %s", value, limit, max.Unit, channelName(channel))
+				}
+			}
+			checkDimension("package_length", item.PackageLength, max.Length)
+			checkDimension("package_width", item.PackageWidth, max.Width)
+			checkDimension("package_height", item.PackageHeight, max.Height)
+			checkDimension("package_dimensions", item.PackageLength+item.PackageWidth+item.PackageHeight, max.DimensionSum)
+		}
+	}
+	switch len(needDimensions) {
+	case 0:
+	case 1:
+		fail("package_dimensions", "channel %s requires the package dimensions", needDimensions[0])
+	default:
+		fail("package_dimensions", "channels %s require the package dimensions", strings.Join(needDimensions, ", "))
+	}
+	if enabled == 0 {
+		fail("logistics", "at least one channel must be enabled")
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// checkLogistics validates the logistics of item when the client is created
+// with WithItemLogisticsValidation. Updates leaving the logistics out are
+// not checked, created items always are. An update is checked against the
+// fields it sets only, its zero weight and package dimensions are ignored.
+func (s *ItemServiceOp) checkLogistics(item *ItemOper, create bool) error {
+	if !s.client.validateItemLogistics || (!create && len(item.Logistics) == 0) {
+		return nil
+	}
+	channels, err := s.client.Logistic.List(item.ShopID)
+	if err != nil {
+		return fmt.Errorf("list logistics channels: %w", err)
+	}
+	return validateItemLogistics(item, channels, !create)
+}
+
+func hasSize(channel *Logistic, sizeID uint64) bool {
+	for _, size := range channel.Sizes {
+		if size.SizeID == sizeID {
+			return true
+		}
+	}
+	return false
+}
+
+func channelName(channel *Logistic) string {
+	if channel.Name != "" {
+		return channel.Name
+	}
+	return fmt.Sprint(channel.ID)
+}
@@ -0,0 +1,200 @@
+package goshopee_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+)
+
+var testChannels = []goshopee.Logistic{
+	{ID: 1, Name: "Standard", Enabled: true, FeeType: goshopee.LogisticFeeFixedDefault,
+		WeightLimits: &goshopee.LogisticWeightLimits{ItemMaxWeight: 5, ItemMinWeight: 0.1}},
+	{ID: 2, Name: "Sized", Enabled: true, FeeType: goshopee.LogisticFeeSizeSelection,
+		Sizes: []goshopee.LogisticSize{{SizeID: 20}}},
+	{ID: 3, Name: "Custom", Enabled: true, FeeType: goshopee.LogisticFeeCustomPrice},
+	{ID: 4, Name: "Bulky", Enabled: true, FeeType: goshopee.LogisticFeeSizeInput,
+		ItemMaxDimension: &goshopee.LogisticDimension{Length: 100, DimensionSum: 150, Unit: "cm"}},
+	{ID: 5, Name: "Retired", Enabled: false},
+	{ID: 6, Name: "Freight", Enabled: true, FeeType: goshopee.LogisticFeeSizeInput},
+}
+
+func TestValidateItemLogistics(t *testing.T) {
+	item := func(weight float64, logistics ...goshopee.Logistic) *goshopee.ItemOper {
+		return &goshopee.ItemOper{ItemBase: goshopee.ItemBase{Weight: weight, Logistics: logistics}}
+	}
+	cases := []struct {
+		name       string
+		item       *goshopee.ItemOper
+		wantFields []string
+	}{
+		{name: "valid", item: item(1, goshopee.Logistic{ID: 1, Enabled: true})},
+		{name: "none enabled", item: item(1, goshopee.Logistic{ID: 1}), wantFields: []string{"logistics"}},
+		{name: "unknown channel", item: item(1, goshopee.Logistic{ID: 9, Enabled: true}), wantFields: []string{"logistics[0].logistic_id"}},
+		{name: "disabled channel", item: item(1, goshopee.Logistic{ID: 5, Enabled: true}), wantFields: []string{"logistics[0].logistic_id"}},
+		{name: "disabled channel not enabled", item: item(1, goshopee.Logistic{ID: 1, Enabled: true}, goshopee.Logistic{ID: 5})},
+		{name: "listed twice", item: item(1, goshopee.Logistic{ID: 1, Enabled: true}, goshopee.Logistic{ID: 1, Enabled: true}), wantFields: []string{"logistics[1].logistic_id"}},
+		{name: "too heavy", item: item(6, goshopee.Logistic{ID: 1, Enabled: true}), wantFields: []string{"weight"}},
+		{name: "too light", item: item(0.05, goshopee.Logistic{ID: 1, Enabled: true}), wantFields: []string{"weight"}},
+		{name: "size", item: item(1, goshopee.Logistic{ID: 2, Enabled: true, SizeID: 20})},
+		{name: "unknown size", item: item(1, goshopee.Logistic{ID: 2, Enabled: true, SizeID: 21}), wantFields: []string{"logistics[0].size_id"}},
+		{name: "custom fee", item: item(1, goshopee.Logistic{ID: 3, Enabled: true, ShippingFee: goshopee.MustParseDecimal("2.5")})},
+		{name: "custom fee free", item: item(1, goshopee.Logistic{ID: 3, Enabled: true, IsFree: true})},
+		{name: "custom fee missing", item: item(1, goshopee.Logistic{ID: 3, Enabled: true}), wantFields: []string{"logistics[0].shipping_fee"}},
+		{name: "negative fee", item: item(1, goshopee.Logistic{ID: 1, Enabled: true, ShippingFee: goshopee.MustParseDecimal("-1")}), wantFields: []string{"logistics[0].shipping_fee"}},
+		{name: "dimensions missing", item: item(1, goshopee.Logistic{ID: 4, Enabled: true}), wantFields: []string{"package_dimensions"}},
+		{name: "dimensions missing for two channels", item: item(1, goshopee.Logistic{ID: 4, Enabled: true}, goshopee.Logistic{ID: 6, Enabled: true}), wantFields: []string{"package_dimensions"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := goshopee.ValidateItemLogistics(tc.item, testChannels)
+			var fields []string
+			var verrs goshopee.ValidationErrors
+			if errors.As(err, &verrs) {
+				for _, fe := range verrs {
+					fields = append(fields, fe.Field)
+				}
+			} else if err != nil {
+				t.Fatalf("error = %v, want ValidationErrors", err)
+			}
+			if !reflect.DeepEqual(fields, tc.wantFields) {
+				t.Errorf("fields = %v (%v), want %v", fields, err, tc.wantFields)
+			}
+		})
+	}
+}
+
+func TestValidateItemLogisticsDimensions(t *testing.T) {
+	item := &goshopee.ItemOper{ItemBase: goshopee.ItemBase{
+		Weight:        1,
+		PackageLength: 110,
+		PackageWidth:  30,
+		PackageHeight: 20,
+		Logistics:     []goshopee.Logistic{{ID: 4, Enabled: true}},
+	}}
+	var verrs goshopee.ValidationErrors
+	if err := goshopee.ValidateItemLogistics(item, testChannels); !errors.As(err, &verrs) {
+		t.Fatalf("error = %v, want ValidationErrors", err)
+	}
+	var fields []string
+	for _, fe := range verrs {
+		fields = append(fields, fe.Field)
+	}
+	if want := []string{"package_length", "package_dimensions"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+}
+
+func TestValidateItemLogisticsDimensionsOnce(t *testing.T) {
+	item := &goshopee.ItemOper{ItemBase: goshopee.ItemBase{
+		Weight:    1,
+		Logistics: []goshopee.Logistic{{ID: 4, Enabled: true}, {ID: 6, Enabled: true}},
+	}}
+	err := goshopee.ValidateItemLogistics(item, testChannels)
+	if want := "package_dimensions: channels Bulky, Freight require the package dimensions"; err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
+
+func TestItemLogisticsValidation(t *testing.T) {
+	newItem := goshopee.ItemOper{ItemBase: goshopee.ItemBase{
+		ShopID:     7,
+		Name:       "tee",
+		CategoryID: 1,
+		Weight:     6,
+		Logistics:  []goshopee.Logistic{{ID: 1, Enabled: true}},
+	}}
+	cases := []struct {
+		name      string
+		opts      []goshopee.Option
+		wantErr   bool
+		wantPaths []string
+	}{
+		// the weight limit is left to Shopee
+		{name: "default", wantPaths: []string{"/item/add"}},
+		{name: "validated", opts: []goshopee.Option{goshopee.WithItemLogisticsValidation()}, wantErr: true, wantPaths: []string{"/logistics/channel/get"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			for _, l := range testChannels {
+				srv.AddLogistic(7, l)
+			}
+
+			_, err := srv.Client(tc.opts...).Item.Create(newItem)
+			var verrs goshopee.ValidationErrors
+			if errors.As(err, &verrs) != tc.wantErr {
+				t.Errorf("Create error = %v, want validation error %t", err, tc.wantErr)
+			}
+			var paths []string
+			for _, r := range srv.Requests() {
+				paths = append(paths, r.Path)
+			}
+			if !reflect.DeepEqual(paths, tc.wantPaths) {
+				t.Errorf("calls = %v, want %v", paths, tc.wantPaths)
+			}
+		})
+	}
+}
+
+func TestItemLogisticsValidationUpdate(t *testing.T) {
+	cases := []struct {
+		name       string
+		update     goshopee.ItemBase
+		wantFields []string
+	}{
+		// the weight and dimensions the update leaves out are kept
+		{name: "logistics only", update: goshopee.ItemBase{Logistics: []goshopee.Logistic{{ID: 1, Enabled: true}}}},
+		{name: "size input logistics only", update: goshopee.ItemBase{Logistics: []goshopee.Logistic{{ID: 4, Enabled: true}, {ID: 6, Enabled: true}}}},
+		{name: "too heavy", update: goshopee.ItemBase{Weight: 6, Logistics: []goshopee.Logistic{{ID: 1, Enabled: true}}}, wantFields: []string{"weight"}},
+		{name: "too light", update: goshopee.ItemBase{Weight: 0.05, Logistics: []goshopee.Logistic{{ID: 1, Enabled: true}}}, wantFields: []string{"weight"}},
+		{name: "too long", update: goshopee.ItemBase{PackageLength: 110, Logistics: []goshopee.Logistic{{ID: 4, Enabled: true}}}, wantFields: []string{"package_length"}},
+		{name: "unknown channel", update: goshopee.ItemBase{Logistics: []goshopee.Logistic{{ID: 9, Enabled: true}}}, wantFields: []string{"logistics[0].logistic_id"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			for _, l := range testChannels {
+				srv.AddLogistic(7, l)
+			}
+			item := srv.AddItem(7, goshopee.Item{ItemBase: goshopee.ItemBase{
+				Name:          "tee",
+				Weight:        1,
+				PackageLength: 30,
+				PackageWidth:  20,
+				PackageHeight: 10,
+			}})
+
+			update := tc.update
+			update.ShopID, update.ItemID = 7, item.ItemID
+			_, err := srv.Client(goshopee.WithItemLogisticsValidation()).Item.Update(update)
+			var fields []string
+			var verrs goshopee.ValidationErrors
+			if errors.As(err, &verrs) {
+				for _, fe := range verrs {
+					fields = append(fields, fe.Field)
+				}
+			} else if err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			if !reflect.DeepEqual(fields, tc.wantFields) {
+				t.Errorf("fields = %v (%v), want %v", fields, err, tc.wantFields)
+			}
+			wantPaths := []string{"/logistics/channel/get"}
+			if tc.wantFields == nil {
+				wantPaths = append(wantPaths, "/item/update")
+			}
+			var paths []string
+			for _, r := range srv.Requests() {
+				paths = append(paths, r.Path)
+			}
+			if !reflect.DeepEqual(paths, wantPaths) {
+				t.Errorf("calls = %v, want %v", paths, wantPaths)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package mocks
+
+import goshopee "github.com/passwind/go-shopee"
+
+var _ goshopee.DiscountService = (*DiscountService)(nil)
+
+// DiscountService is a programmable goshopee.DiscountService.
+type DiscountService struct {
+	Recorder
+
+	AddDiscountFunc         func(sid uint64, req map[string]interface{}) (*goshopee.DiscountResponse, error)
+	DeleteDiscountFunc      func(sid, discountID uint64) (*goshopee.DiscountActionResponse, error)
+	AddDiscountItemFunc     func(sid, discountID uint64, req map[string]interface{}) (*goshopee.DiscountResponse, error)
+	DeleteDiscountItemFunc  func(sid, discountID, itemID, variationID uint64) (*goshopee.DiscountActionResponse, error)
+	UpdateDiscountFunc      func(sid, discountID uint64, req map[string]interface{}) (*goshopee.DiscountActionResponse, error)
+	UpdateDiscountItemsFunc func(sid, discountID uint64, req map[string]interface{}) (*goshopee.DiscountResponse, error)
+}
+
+func (m *DiscountService) AddDiscount(sid uint64, req map[string]interface{}) (*goshopee.DiscountResponse, error) {
+	m.record("AddDiscount", sid, req)
+	if m.AddDiscountFunc == nil {
+		return nil, nil
+	}
+	return m.AddDiscountFunc(sid, req)
+}
+
+func (m *DiscountService) DeleteDiscount(sid, discountID uint64) (*goshopee.DiscountActionResponse, error) {
+	m.record("DeleteDiscount", sid, discountID)
+	if m.DeleteDiscountFunc == nil {
+		return nil, nil
+	}
+	return m.DeleteDiscountFunc(sid, discountID)
+}
+
+func (m *DiscountService) AddDiscountItem(sid, discountID uint64, req map[string]interface{}) (*goshopee.DiscountResponse, error) {
+	m.record("AddDiscountItem", sid, discountID, req)
+	if m.AddDiscountItemFunc == nil {
+		return nil, nil
+	}
+	return m.AddDiscountItemFunc(sid, discountID, req)
+}
+
+func (m *DiscountService) DeleteDiscountItem(sid, discountID, itemID, variationID uint64) (*goshopee.DiscountActionResponse, error) {
+	m.record("DeleteDiscountItem", sid, discountID, itemID, variationID)
+	if m.DeleteDiscountItemFunc == nil {
+		return nil, nil
+	}
+	return m.DeleteDiscountItemFunc(sid, discountID, itemID, variationID)
+}
+
+func (m *DiscountService) UpdateDiscount(sid, discountID uint64, req map[string]interface{}) (*goshopee.DiscountActionResponse, error) {
+	m.record("UpdateDiscount", sid, discountID, req)
+	if m.UpdateDiscountFunc == nil {
+		return nil, nil
+	}
+	return m.UpdateDiscountFunc(sid, discountID, req)
+}
+
+func (m *DiscountService) UpdateDiscountItems(sid, discountID uint64, req map[string]interface{}) (*goshopee.DiscountResponse, error) {
+	m.record("UpdateDiscountItems", sid, discountID, req)
+	if m.UpdateDiscountItemsFunc == nil {
+		return nil, nil
+	}
+	return m.UpdateDiscountItemsFunc(sid, discountID, req)
+}
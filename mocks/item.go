@@ -0,0 +1,177 @@
+package mocks
+
+import (
+	"context"
+
+	goshopee "github.com/passwind/go-shopee"
+)
+
+var _ goshopee.ItemService = (*ItemService)(nil)
+
+// ItemService is a programmable goshopee.ItemService.
+type ItemService struct {
+	Recorder
+
+	ListFunc                     func(ctx context.Context, sid uint64, options *goshopee.ItemListOptions) ([]goshopee.Item, error)
+	ListWithPaginationFunc       func(sid uint64, offset, limit uint32, options interface{}) ([]goshopee.Item, *goshopee.Pagination, error)
+	CountFunc                    func(ctx context.Context, sid uint64, options *goshopee.ItemListOptions) (int, error)
+	IterFunc                     func(ctx context.Context, sid uint64, options *goshopee.ItemListOptions, from goshopee.Cursor) *goshopee.Pager[goshopee.Item]
+	GetFunc                      func(sid, itemid uint64) (*goshopee.Item, error)
+	CreateFunc                   func(newItem goshopee.ItemOper) (*goshopee.Item, error)
+	UpdateFunc                   func(item goshopee.ItemBase) (*goshopee.Item, error)
+	UpdatePriceFunc              func(sid, itemid uint64, price goshopee.Decimal) (*goshopee.ItemPriceOper, error)
+	UpdateStockFunc              func(sid, itemid uint64, stock uint32) (*goshopee.ItemStockOper, error)
+	UpdateLogisticsFunc          func(ctx context.Context, sid, itemid uint64, logistics []goshopee.ItemLogistic) (*goshopee.Item, error)
+	UpdateLogisticsAllFunc       func(ctx context.Context, sid uint64, change goshopee.ItemLogistic, options *goshopee.ItemListOptions) ([]goshopee.ItemLogisticsResult, error)
+	DeleteFunc                   func(sid, itemid uint64) error
+	UnlistItemFunc               func(sid, itemid uint64, unlist bool) ([]goshopee.UnlistItemSuccess, []goshopee.UnlistItemFailed, error)
+	InitTierVariationFunc        func(sid, itemid uint64, tierVariations []goshopee.TierVariation, variations []goshopee.TierVariationOperDef) ([]goshopee.Variation, error)
+	AddTierVariationFunc         func(sid, itemid uint64, variations []goshopee.TierVariationOperDef) ([]goshopee.Variation, error)
+	GetVariationsFunc            func(sid, itemid uint64) ([]goshopee.TierVariation, []goshopee.Variation, error)
+	UpdateTierVariationListFunc  func(sid, itemid uint64, tierVariations []goshopee.TierVariation) error
+	UpdateTierVariationIndexFunc func(sid, itemid uint64, variations []goshopee.TierVariationIndexOperDef) error
+}
+
+func (m *ItemService) List(ctx context.Context, sid uint64, options *goshopee.ItemListOptions) ([]goshopee.Item, error) {
+	m.record("List", ctx, sid, options)
+	if m.ListFunc == nil {
+		return nil, nil
+	}
+	return m.ListFunc(ctx, sid, options)
+}
+
+func (m *ItemService) ListWithPagination(sid uint64, offset, limit uint32, options interface{}) ([]goshopee.Item, *goshopee.Pagination, error) {
+	m.record("ListWithPagination", sid, offset, limit, options)
+	if m.ListWithPaginationFunc == nil {
+		return nil, nil, nil
+	}
+	return m.ListWithPaginationFunc(sid, offset, limit, options)
+}
+
+func (m *ItemService) Count(ctx context.Context, sid uint64, options *goshopee.ItemListOptions) (int, error) {
+	m.record("Count", ctx, sid, options)
+	if m.CountFunc == nil {
+		return 0, nil
+	}
+	return m.CountFunc(ctx, sid, options)
+}
+
+func (m *ItemService) Iter(ctx context.Context, sid uint64, options *goshopee.ItemListOptions, from goshopee.Cursor) *goshopee.Pager[goshopee.Item] {
+	m.record("Iter", ctx, sid, options, from)
+	if m.IterFunc == nil {
+		return emptyPager[goshopee.Item](ctx, from)
+	}
+	return m.IterFunc(ctx, sid, options, from)
+}
+
+func (m *ItemService) Get(sid, itemid uint64) (*goshopee.Item, error) {
+	m.record("Get", sid, itemid)
+	if m.GetFunc == nil {
+		return nil, nil
+	}
+	return m.GetFunc(sid, itemid)
+}
+
+func (m *ItemService) Create(newItem goshopee.ItemOper) (*goshopee.Item, error) {
+	m.record("Create", newItem)
+	if m.CreateFunc == nil {
+		return nil, nil
+	}
+	return m.CreateFunc(newItem)
+}
+
+func (m *ItemService) Update(item goshopee.ItemBase) (*goshopee.Item, error) {
+	m.record("Update", item)
+	if m.UpdateFunc == nil {
+		return nil, nil
+	}
+	return m.UpdateFunc(item)
+}
+
+func (m *ItemService) UpdatePrice(sid, itemid uint64, price goshopee.Decimal) (*goshopee.ItemPriceOper, error) {
+	m.record("UpdatePrice", sid, itemid, price)
+	if m.UpdatePriceFunc == nil {
+		return nil, nil
+	}
+	return m.UpdatePriceFunc(sid, itemid, price)
+}
+
+func (m *ItemService) UpdateStock(sid, itemid uint64, stock uint32) (*goshopee.ItemStockOper, error) {
+	m.record("UpdateStock", sid, itemid, stock)
+	if m.UpdateStockFunc == nil {
+		return nil, nil
+	}
+	return m.UpdateStockFunc(sid, itemid, stock)
+}
+
+func (m *ItemService) UpdateLogistics(ctx context.Context, sid, itemid uint64, logistics []goshopee.ItemLogistic) (*goshopee.Item, error) {
+	m.record("UpdateLogistics", sid, itemid, logistics)
+	if m.UpdateLogisticsFunc == nil {
+		return nil, nil
+	}
+	return m.UpdateLogisticsFunc(ctx, sid, itemid, logistics)
+}
+
+func (m *ItemService) UpdateLogisticsAll(ctx context.Context, sid uint64, change goshopee.ItemLogistic, options *goshopee.ItemListOptions) ([]goshopee.ItemLogisticsResult, error) {
+	m.record("UpdateLogisticsAll", sid, change, options)
+	if m.UpdateLogisticsAllFunc == nil {
+		return nil, nil
+	}
+	return m.UpdateLogisticsAllFunc(ctx, sid, change, options)
+}
+
+func (m *ItemService) Delete(sid, itemid uint64) error {
+	m.record("Delete", sid, itemid)
+	if m.DeleteFunc == nil {
+		return nil
+	}
+	return m.DeleteFunc(sid, itemid)
+}
+
+func (m *ItemService) UnlistItem(sid, itemid uint64, unlist bool) ([]goshopee.UnlistItemSuccess, []goshopee.UnlistItemFailed, error) {
+	m.record("UnlistItem", sid, itemid, unlist)
+	if m.UnlistItemFunc == nil {
+		return nil, nil, nil
+	}
+	return m.UnlistItemFunc(sid, itemid, unlist)
+}
+
+func (m *ItemService) InitTierVariation(sid, itemid uint64, tierVariations []goshopee.TierVariation, variations []goshopee.TierVariationOperDef) ([]goshopee.Variation, error) {
+	m.record("InitTierVariation", sid, itemid, tierVariations, variations)
+	if m.InitTierVariationFunc == nil {
+		return nil, nil
+	}
+	return m.InitTierVariationFunc(sid, itemid, tierVariations, variations)
+}
+
+func (m *ItemService) AddTierVariation(sid, itemid uint64, variations []goshopee.TierVariationOperDef) ([]goshopee.Variation, error) {
+	m.record("AddTierVariation", sid, itemid, variations)
+	if m.AddTierVariationFunc == nil {
+		return nil, nil
+	}
+	return m.AddTierVariationFunc(sid, itemid, variations)
+}
+
+func (m *ItemService) GetVariations(sid, itemid uint64) ([]goshopee.TierVariation, []goshopee.Variation, error) {
+	m.record("GetVariations", sid, itemid)
+	if m.GetVariationsFunc == nil {
+		return nil, nil, nil
+	}
+	return m.GetVariationsFunc(sid, itemid)
+}
+
+func (m *ItemService) UpdateTierVariationList(sid, itemid uint64, tierVariations []goshopee.TierVariation) error {
+	m.record("UpdateTierVariationList", sid, itemid, tierVariations)
+	if m.UpdateTierVariationListFunc == nil {
+		return nil
+	}
+	return m.UpdateTierVariationListFunc(sid, itemid, tierVariations)
+}
+
+func (m *ItemService) UpdateTierVariationIndex(sid, itemid uint64, variations []goshopee.TierVariationIndexOperDef) error {
+	m.record("UpdateTierVariationIndex", sid, itemid, variations)
+	if m.UpdateTierVariationIndexFunc == nil {
+		return nil
+	}
+	return m.UpdateTierVariationIndexFunc(sid, itemid, variations)
+}
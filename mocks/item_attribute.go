@@ -0,0 +1,20 @@
+package mocks
+
+import goshopee "github.com/passwind/go-shopee"
+
+var _ goshopee.ItemAttributeService = (*ItemAttributeService)(nil)
+
+// ItemAttributeService is a programmable goshopee.ItemAttributeService.
+type ItemAttributeService struct {
+	Recorder
+
+	ListFunc func(cid uint64, options map[string]interface{}) ([]goshopee.ItemAttribute, error)
+}
+
+func (m *ItemAttributeService) List(cid uint64, options map[string]interface{}) ([]goshopee.ItemAttribute, error) {
+	m.record("List", cid, options)
+	if m.ListFunc == nil {
+		return nil, nil
+	}
+	return m.ListFunc(cid, options)
+}
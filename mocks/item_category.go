@@ -0,0 +1,20 @@
+package mocks
+
+import goshopee "github.com/passwind/go-shopee"
+
+var _ goshopee.ItemCategoryService = (*ItemCategoryService)(nil)
+
+// ItemCategoryService is a programmable goshopee.ItemCategoryService.
+type ItemCategoryService struct {
+	Recorder
+
+	ListFunc func(sid uint64, options map[string]interface{}) ([]goshopee.ItemCategory, error)
+}
+
+func (m *ItemCategoryService) List(sid uint64, options map[string]interface{}) ([]goshopee.ItemCategory, error) {
+	m.record("List", sid, options)
+	if m.ListFunc == nil {
+		return nil, nil
+	}
+	return m.ListFunc(sid, options)
+}
@@ -0,0 +1,87 @@
+package mocks
+
+import (
+	"context"
+
+	goshopee "github.com/passwind/go-shopee"
+)
+
+var _ goshopee.LogisticService = (*LogisticService)(nil)
+
+// LogisticService is a programmable goshopee.LogisticService.
+type LogisticService struct {
+	Recorder
+
+	InitFunc                func(sid uint64, ordersn string, req goshopee.LogisticInitRequest, params *goshopee.GetParameterForInitResponse) (*goshopee.LogisticInitResponse, error)
+	GetParameterForInitFunc func(sid uint64, ordersn string) (*goshopee.GetParameterForInitResponse, error)
+	GetLogisticInfoFunc     func(sid uint64, ordersn string) (*goshopee.GetLogisticInfoResponse, error)
+	ListFunc                func(sid uint64) ([]goshopee.Logistic, error)
+	GetAirwayBillFunc       func(sid uint64, ordersn []string, batch bool) (*goshopee.AirwayBillResponse, error)
+	DownloadAirwayBillsFunc func(ctx context.Context, sid uint64, ordersn []string, merge bool, w goshopee.AirwayBillWriter) (*goshopee.AirwayBillDownload, error)
+	GetTrackingInfoFunc     func(sid uint64, ordersn string) (*goshopee.TrackingInfo, error)
+	WaitTrackingNumberFunc  func(ctx context.Context, sid uint64, ordersn string, opts *goshopee.TrackingPollOptions) (string, error)
+}
+
+func (m *LogisticService) Init(sid uint64, ordersn string, req goshopee.LogisticInitRequest, params *goshopee.GetParameterForInitResponse) (*goshopee.LogisticInitResponse, error) {
+	m.record("Init", sid, ordersn, req, params)
+	if m.InitFunc == nil {
+		return nil, nil
+	}
+	return m.InitFunc(sid, ordersn, req, params)
+}
+
+func (m *LogisticService) GetParameterForInit(sid uint64, ordersn string) (*goshopee.GetParameterForInitResponse, error) {
+	m.record("GetParameterForInit", sid, ordersn)
+	if m.GetParameterForInitFunc == nil {
+		return nil, nil
+	}
+	return m.GetParameterForInitFunc(sid, ordersn)
+}
+
+func (m *LogisticService) GetLogisticInfo(sid uint64, ordersn string) (*goshopee.GetLogisticInfoResponse, error) {
+	m.record("GetLogisticInfo", sid, ordersn)
+	if m.GetLogisticInfoFunc == nil {
+		return nil, nil
+	}
+	return m.GetLogisticInfoFunc(sid, ordersn)
+}
+
+func (m *LogisticService) List(sid uint64) ([]goshopee.Logistic, error) {
+	m.record("List", sid)
+	if m.ListFunc == nil {
+		return nil, nil
+	}
+	return m.ListFunc(sid)
+}
+
+func (m *LogisticService) GetAirwayBill(sid uint64, ordersn []string, batch bool) (*goshopee.AirwayBillResponse, error) {
+	m.record("GetAirwayBill", sid, ordersn, batch)
+	if m.GetAirwayBillFunc == nil {
+		return nil, nil
+	}
+	return m.GetAirwayBillFunc(sid, ordersn, batch)
+}
+
+func (m *LogisticService) DownloadAirwayBills(ctx context.Context, sid uint64, ordersn []string, merge bool, w goshopee.AirwayBillWriter) (*goshopee.AirwayBillDownload, error) {
+	m.record("DownloadAirwayBills", sid, ordersn, merge)
+	if m.DownloadAirwayBillsFunc == nil {
+		return nil, nil
+	}
+	return m.DownloadAirwayBillsFunc(ctx, sid, ordersn, merge, w)
+}
+
+func (m *LogisticService) GetTrackingInfo(sid uint64, ordersn string) (*goshopee.TrackingInfo, error) {
+	m.record("GetTrackingInfo", sid, ordersn)
+	if m.GetTrackingInfoFunc == nil {
+		return nil, nil
+	}
+	return m.GetTrackingInfoFunc(sid, ordersn)
+}
+
+func (m *LogisticService) WaitTrackingNumber(ctx context.Context, sid uint64, ordersn string, opts *goshopee.TrackingPollOptions) (string, error) {
+	m.record("WaitTrackingNumber", sid, ordersn, opts)
+	if m.WaitTrackingNumberFunc == nil {
+		return "", nil
+	}
+	return m.WaitTrackingNumberFunc(ctx, sid, ordersn, opts)
+}
@@ -0,0 +1,93 @@
+// Package mocks provides programmable test doubles for the goshopee service
+// interfaces.
+//
+// Every mock records the calls it receives and delegates to an optional
+// function field named after the method, returning zero values when the
+// field is nil:
+//
+//	orders := &mocks.OrderService{
+//		GetFunc: func(sid uint64, ordersn string) (*goshopee.Order, error) {
+//			return &goshopee.Order{OrderSN: ordersn}, nil
+//		},
+//	}
+//	client.Order = orders
+//	...
+//	if n := len(orders.CallsTo("Get")); n != 1 {
+//		t.Errorf("Get called %d times", n)
+//	}
+//
+// The mocks assert at compile time that they implement their interface, so
+// they cannot get out of sync with the services.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	goshopee "github.com/passwind/go-shopee"
+)
+
+// Call is a method call received by a mock.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Recorder records the calls made to a mock, it is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *Recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns every call received, in order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the calls received by method, in order.
+func (r *Recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// NewPager returns a Pager over fixed pages, e.g. to return from an IterFunc.
+func NewPager[T any](ctx context.Context, from goshopee.Cursor, pages ...[]T) *goshopee.Pager[T] {
+	fetch := func(ctx context.Context, offset uint32) ([]T, bool, error) {
+		var start uint32
+		for i, page := range pages {
+			end := start + uint32(len(page))
+			if offset < end {
+				return page[offset-start:], i < len(pages)-1, nil
+			}
+			start = end
+		}
+		return nil, false, nil
+	}
+	return goshopee.NewPager(ctx, fetch, from)
+}
+
+func emptyPager[T any](ctx context.Context, from goshopee.Cursor) *goshopee.Pager[T] {
+	return NewPager[T](ctx, from)
+}
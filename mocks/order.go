@@ -0,0 +1,142 @@
+package mocks
+
+import (
+	"context"
+	"iter"
+
+	goshopee "github.com/passwind/go-shopee"
+)
+
+var _ goshopee.OrderService = (*OrderService)(nil)
+
+// OrderService is a programmable goshopee.OrderService.
+type OrderService struct {
+	Recorder
+
+	ListFunc                    func(sid uint64) ([]goshopee.Order, error)
+	ListWithPaginationFunc      func(sid uint64, offset, limit uint32, options map[string]interface{}) ([]goshopee.Order, *goshopee.Pagination, error)
+	IterFunc                    func(ctx context.Context, sid uint64, options map[string]interface{}, from goshopee.Cursor) *goshopee.Pager[goshopee.Order]
+	QueryFunc                   func(ctx context.Context, sid uint64, query goshopee.OrderQuery) iter.Seq2[goshopee.Order, error]
+	CountFunc                   func(ctx context.Context, sid uint64, query goshopee.OrderQuery) (map[goshopee.OrderStatus]int, error)
+	GetFunc                     func(sid uint64, ordersn string) (*goshopee.Order, error)
+	GetMultiFunc                func(ctx context.Context, sid uint64, orders []string) ([]goshopee.Order, []string, error)
+	CancelFunc                  func(sid uint64, ordersn, reason string, options map[string]interface{}) error
+	AddNoteFunc                 func(sid uint64, ordersn, note string) error
+	SplitOrderFunc              func(sid uint64, ordersn string, parcels [][]goshopee.OrderSplitItem) (*goshopee.OrderSplitResponse, error)
+	UndoSplitOrderFunc          func(sid uint64, ordersn string) error
+	AcceptBuyerCancellationFunc func(sid uint64, ordersn string) error
+	RejectBuyerCancellationFunc func(sid uint64, ordersn string) error
+	GetEscrowDetailFunc         func(sid uint64, ordersn string) (*goshopee.EscrowDetail, error)
+}
+
+func (m *OrderService) List(sid uint64) ([]goshopee.Order, error) {
+	m.record("List", sid)
+	if m.ListFunc == nil {
+		return nil, nil
+	}
+	return m.ListFunc(sid)
+}
+
+func (m *OrderService) ListWithPagination(sid uint64, offset, limit uint32, options map[string]interface{}) ([]goshopee.Order, *goshopee.Pagination, error) {
+	m.record("ListWithPagination", sid, offset, limit, options)
+	if m.ListWithPaginationFunc == nil {
+		return nil, nil, nil
+	}
+	return m.ListWithPaginationFunc(sid, offset, limit, options)
+}
+
+func (m *OrderService) Iter(ctx context.Context, sid uint64, options map[string]interface{}, from goshopee.Cursor) *goshopee.Pager[goshopee.Order] {
+	m.record("Iter", ctx, sid, options, from)
+	if m.IterFunc == nil {
+		return emptyPager[goshopee.Order](ctx, from)
+	}
+	return m.IterFunc(ctx, sid, options, from)
+}
+
+func (m *OrderService) Query(ctx context.Context, sid uint64, query goshopee.OrderQuery) iter.Seq2[goshopee.Order, error] {
+	m.record("Query", ctx, sid, query)
+	if m.QueryFunc == nil {
+		return func(yield func(goshopee.Order, error) bool) {}
+	}
+	return m.QueryFunc(ctx, sid, query)
+}
+
+func (m *OrderService) Count(ctx context.Context, sid uint64, query goshopee.OrderQuery) (map[goshopee.OrderStatus]int, error) {
+	m.record("Count", ctx, sid, query)
+	if m.CountFunc == nil {
+		return nil, nil
+	}
+	return m.CountFunc(ctx, sid, query)
+}
+
+func (m *OrderService) Get(sid uint64, ordersn string) (*goshopee.Order, error) {
+	m.record("Get", sid, ordersn)
+	if m.GetFunc == nil {
+		return nil, nil
+	}
+	return m.GetFunc(sid, ordersn)
+}
+
+func (m *OrderService) GetMulti(ctx context.Context, sid uint64, orders []string) ([]goshopee.Order, []string, error) {
+	m.record("GetMulti", ctx, sid, orders)
+	if m.GetMultiFunc == nil {
+		return nil, nil, nil
+	}
+	return m.GetMultiFunc(ctx, sid, orders)
+}
+
+func (m *OrderService) Cancel(sid uint64, ordersn, reason string, options map[string]interface{}) error {
+	m.record("Cancel", sid, ordersn, reason, options)
+	if m.CancelFunc == nil {
+		return nil
+	}
+	return m.CancelFunc(sid, ordersn, reason, options)
+}
+
+func (m *OrderService) AddNote(sid uint64, ordersn, note string) error {
+	m.record("AddNote", sid, ordersn, note)
+	if m.AddNoteFunc == nil {
+		return nil
+	}
+	return m.AddNoteFunc(sid, ordersn, note)
+}
+
+func (m *OrderService) SplitOrder(sid uint64, ordersn string, parcels [][]goshopee.OrderSplitItem) (*goshopee.OrderSplitResponse, error) {
+	m.record("SplitOrder", sid, ordersn, parcels)
+	if m.SplitOrderFunc == nil {
+		return nil, nil
+	}
+	return m.SplitOrderFunc(sid, ordersn, parcels)
+}
+
+func (m *OrderService) UndoSplitOrder(sid uint64, ordersn string) error {
+	m.record("UndoSplitOrder", sid, ordersn)
+	if m.UndoSplitOrderFunc == nil {
+		return nil
+	}
+	return m.UndoSplitOrderFunc(sid, ordersn)
+}
+
+func (m *OrderService) AcceptBuyerCancellation(sid uint64, ordersn string) error {
+	m.record("AcceptBuyerCancellation", sid, ordersn)
+	if m.AcceptBuyerCancellationFunc == nil {
+		return nil
+	}
+	return m.AcceptBuyerCancellationFunc(sid, ordersn)
+}
+
+func (m *OrderService) RejectBuyerCancellation(sid uint64, ordersn string) error {
+	m.record("RejectBuyerCancellation", sid, ordersn)
+	if m.RejectBuyerCancellationFunc == nil {
+		return nil
+	}
+	return m.RejectBuyerCancellationFunc(sid, ordersn)
+}
+
+func (m *OrderService) GetEscrowDetail(sid uint64, ordersn string) (*goshopee.EscrowDetail, error) {
+	m.record("GetEscrowDetail", sid, ordersn)
+	if m.GetEscrowDetailFunc == nil {
+		return nil, nil
+	}
+	return m.GetEscrowDetailFunc(sid, ordersn)
+}
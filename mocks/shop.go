@@ -0,0 +1,20 @@
+package mocks
+
+import goshopee "github.com/passwind/go-shopee"
+
+var _ goshopee.ShopService = (*ShopService)(nil)
+
+// ShopService is a programmable goshopee.ShopService.
+type ShopService struct {
+	Recorder
+
+	GetFunc func(sid uint64) (*goshopee.Shop, error)
+}
+
+func (m *ShopService) Get(sid uint64) (*goshopee.Shop, error) {
+	m.record("Get", sid)
+	if m.GetFunc == nil {
+		return nil, nil
+	}
+	return m.GetFunc(sid)
+}
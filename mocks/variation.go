@@ -0,0 +1,56 @@
+package mocks
+
+import goshopee "github.com/passwind/go-shopee"
+
+var _ goshopee.VariationService = (*VariationService)(nil)
+
+// VariationService is a programmable goshopee.VariationService.
+type VariationService struct {
+	Recorder
+
+	CreateFunc                    func(sid, itemID uint64, variation goshopee.Variation) (*goshopee.Variation, error)
+	DeleteFunc                    func(sid, itemID, variationID uint64) error
+	UpdateVariationPriceFunc      func(sid, itemID uint64, variation goshopee.Variation) (*goshopee.Variation, error)
+	UpdateVariationStockFunc      func(sid, itemID uint64, variation goshopee.Variation) (*goshopee.Variation, error)
+	UpdateVariationPriceBatchFunc func(sid uint64, params []goshopee.VariationPriceRequest) (*goshopee.VariationPriceResponse, error)
+}
+
+func (m *VariationService) Create(sid, itemID uint64, variation goshopee.Variation) (*goshopee.Variation, error) {
+	m.record("Create", sid, itemID, variation)
+	if m.CreateFunc == nil {
+		return nil, nil
+	}
+	return m.CreateFunc(sid, itemID, variation)
+}
+
+func (m *VariationService) Delete(sid, itemID, variationID uint64) error {
+	m.record("Delete", sid, itemID, variationID)
+	if m.DeleteFunc == nil {
+		return nil
+	}
+	return m.DeleteFunc(sid, itemID, variationID)
+}
+
+func (m *VariationService) UpdateVariationPrice(sid, itemID uint64, variation goshopee.Variation) (*goshopee.Variation, error) {
+	m.record("UpdateVariationPrice", sid, itemID, variation)
+	if m.UpdateVariationPriceFunc == nil {
+		return nil, nil
+	}
+	return m.UpdateVariationPriceFunc(sid, itemID, variation)
+}
+
+func (m *VariationService) UpdateVariationStock(sid, itemID uint64, variation goshopee.Variation) (*goshopee.Variation, error) {
+	m.record("UpdateVariationStock", sid, itemID, variation)
+	if m.UpdateVariationStockFunc == nil {
+		return nil, nil
+	}
+	return m.UpdateVariationStockFunc(sid, itemID, variation)
+}
+
+func (m *VariationService) UpdateVariationPriceBatch(sid uint64, params []goshopee.VariationPriceRequest) (*goshopee.VariationPriceResponse, error) {
+	m.record("UpdateVariationPriceBatch", sid, params)
+	if m.UpdateVariationPriceBatchFunc == nil {
+		return nil, nil
+	}
+	return m.UpdateVariationPriceBatchFunc(sid, params)
+}
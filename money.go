@@ -0,0 +1,351 @@
+package goshopee
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, used for every amount of money so
+// prices and totals do not suffer from float64 rounding. The zero value is 0.
+//
+// It decodes from both JSON strings ("12.30") and numbers (12.3), an empty
+// string or null decode as 0, and it encodes as a JSON number.
+type Decimal struct {
+	unscaled *big.Int // nil is zero
+	scale    int32    // value = unscaled * 10^-scale
+}
+
+// NewDecimal returns unscaled * 10^-scale, e.g. NewDecimal(1230, 2) is 12.30.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+// NewDecimalFromFloat returns the shortest decimal representing f.
+func NewDecimalFromFloat(f float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		// FormatFloat only fails to produce a number for NaN and Inf
+		return Decimal{}
+	}
+	return d
+}
+
+// maxDecimalScale bounds the scale of parsed decimals, far beyond any amount
+// of money but small enough to keep the arithmetic cheap.
+const maxDecimalScale = 64
+
+// ParseDecimal parses a decimal number such as "-12.30" or "1.5e3". Numbers
+// with more than 64 fractional digits or an exponent beyond 64, once
+// normalized, are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	str := strings.TrimSpace(s)
+	if str == "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	var exp int64
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+		exp = e
+		str = str[:i]
+	}
+
+	digits := str
+	var scale int64
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		digits = str[:i] + str[i+1:]
+		scale = int64(len(str) - i - 1)
+	}
+	if digits == "" || digits == "-" || digits == "+" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	for i, c := range digits {
+		if (c < '0' || c > '9') && !(i == 0 && (c == '-' || c == '+')) {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+
+	if scale-exp > maxDecimalScale || scale-exp < -maxDecimalScale {
+		return Decimal{}, fmt.Errorf("decimal %q out of range", s)
+	}
+
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{unscaled: unscaled, scale: int32(scale - exp)}, nil
+}
+
+// MustParseDecimal is ParseDecimal panicking on invalid input, for constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// rescale returns the unscaled value of d at a scale at least d.scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	v := new(big.Int).Set(d.int())
+	if scale > d.scale {
+		v.Mul(v, pow10(scale-d.scale))
+	}
+	return v
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func maxScale(a, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+// Add returns d + o.
+func (d Decimal) Add(o Decimal) Decimal {
+	scale := maxScale(d, o)
+	return Decimal{unscaled: new(big.Int).Add(d.rescale(scale), o.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - o.
+func (d Decimal) Sub(o Decimal) Decimal {
+	scale := maxScale(d, o)
+	return Decimal{unscaled: new(big.Int).Sub(d.rescale(scale), o.rescale(scale)), scale: scale}
+}
+
+// Mul returns d * o.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), o.int()), scale: d.scale + o.scale}
+}
+
+// MulInt returns d * n, e.g. a unit price times a quantity.
+func (d Decimal) MulInt(n int64) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), big.NewInt(n)), scale: d.scale}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Cmp returns -1, 0 or 1 when d is less than, equal to or greater than o.
+func (d Decimal) Cmp(o Decimal) int {
+	scale := maxScale(d, o)
+	return d.rescale(scale).Cmp(o.rescale(scale))
+}
+
+// Equal reports whether d and o are the same number, 1.5 equals 1.50.
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
+
+// Sign returns -1, 0 or 1 when d is negative, zero or positive.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Round rounds d to the given number of decimal places, halves away from
+// zero.
+func (d Decimal) Round(places int32) Decimal {
+	if d.scale <= places {
+		return Decimal{unscaled: d.rescale(places), scale: places}
+	}
+	divisor := pow10(d.scale - places)
+	q, r := new(big.Int).QuoRem(new(big.Int).Abs(d.int()), divisor, new(big.Int))
+	if r.Mul(r, big.NewInt(2)).Cmp(divisor) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if d.Sign() < 0 {
+		q.Neg(q)
+	}
+	return Decimal{unscaled: q, scale: places}
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d without exponent, e.g. "12.30".
+func (d Decimal) String() string {
+	v := d.int()
+	if d.scale <= 0 {
+		return new(big.Int).Mul(v, pow10(-d.scale)).String()
+	}
+	digits := new(big.Int).Abs(v).String()
+	if pad := int(d.scale) - len(digits) + 1; pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	i := len(digits) - int(d.scale)
+	s := digits[:i] + "." + digits[i:]
+	if v.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// MarshalJSON encodes d as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes d from a JSON number or string.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return fmt.Errorf("invalid decimal %s", s)
+		}
+		s = unquoted
+		if strings.TrimSpace(s) == "" {
+			*d = Decimal{}
+			return nil
+		}
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// currencyDecimals is the number of decimal places of the currencies which
+// do not use cents. Every other currency uses 2.
+var currencyDecimals = map[string]int32{
+	"IDR": 0,
+	"VND": 0,
+	"TWD": 0,
+	"CLP": 0,
+	"COP": 0,
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// countryCurrencies maps the Shopee countries to their currency.
+var countryCurrencies = map[string]string{
+	"SG": "SGD",
+	"MY": "MYR",
+	"TH": "THB",
+	"ID": "IDR",
+	"VN": "VND",
+	"PH": "PHP",
+	"TW": "TWD",
+	"BR": "BRL",
+	"MX": "MXN",
+	"CO": "COP",
+	"CL": "CLP",
+	"PL": "PLN",
+	"ES": "EUR",
+	"FR": "EUR",
+	"IN": "INR",
+}
+
+// CurrencyDecimals returns the number of decimal places amounts in currency
+// are rounded to, e.g. 2 for SGD and 0 for IDR.
+func CurrencyDecimals(currency string) int32 {
+	if places, ok := currencyDecimals[strings.ToUpper(currency)]; ok {
+		return places
+	}
+	return 2
+}
+
+// CountryCurrency returns the currency of a Shopee country code, e.g. "SGD"
+// for "SG", or an empty string when the country is unknown.
+func CountryCurrency(country string) string {
+	return countryCurrencies[strings.ToUpper(country)]
+}
+
+// Money is an amount in a currency.
+type Money struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// NewMoney returns amount in currency.
+func NewMoney(amount Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+func (m Money) check(o Money) error {
+	if m.Currency != o.Currency && !m.Amount.IsZero() && !o.Amount.IsZero() {
+		return fmt.Errorf("currency mismatch: %s and %s", m.Currency, o.Currency)
+	}
+	return nil
+}
+
+func (m Money) currency(o Money) string {
+	if m.Currency == "" || m.Amount.IsZero() {
+		return o.Currency
+	}
+	return m.Currency
+}
+
+// Add returns m + o, both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Add(o.Amount), Currency: m.currency(o)}, nil
+}
+
+// Sub returns m - o, both must be in the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Sub(o.Amount), Currency: m.currency(o)}, nil
+}
+
+// MulInt returns m * n.
+func (m Money) MulInt(n int64) Money {
+	return Money{Amount: m.Amount.MulInt(n), Currency: m.Currency}
+}
+
+// Round rounds m to the decimal places of its currency.
+func (m Money) Round() Money {
+	return Money{Amount: m.Amount.Round(CurrencyDecimals(m.Currency)), Currency: m.Currency}
+}
+
+// String formats m rounded to its currency, e.g. "12.30 SGD".
+func (m Money) String() string {
+	return m.Round().Amount.String() + " " + m.Currency
+}
+
+// Money returns amount, e.g. o.TotalAmount, in the currency of the order.
+func (o *Order) Money(amount Decimal) Money {
+	return NewMoney(amount, o.Currency)
+}
+
+// Money returns amount, e.g. i.Price, in the currency of the item.
+func (i *ItemBase) Money(amount Decimal) Money {
+	return NewMoney(amount, i.Currency)
+}
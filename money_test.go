@@ -0,0 +1,167 @@
+package goshopee
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "0", want: "0"},
+		{in: "12.30", want: "12.30"},
+		{in: "-12.30", want: "-12.30"},
+		{in: "+7", want: "7"},
+		{in: " 1.5 ", want: "1.5"},
+		{in: ".5", want: "0.5"},
+		{in: "-.05", want: "-0.05"},
+		{in: "1.", want: "1"},
+		{in: "1.5e3", want: "1500"},
+		{in: "15E-3", want: "0.015"},
+		{in: "123456789012345678901234567890.01", want: "123456789012345678901234567890.01"},
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "1,5", wantErr: true},
+		{in: "1e", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "--1", wantErr: true},
+		{in: "1e64", want: "1" + strings.Repeat("0", 64)},
+		{in: "1e-64", want: "0." + strings.Repeat("0", 63) + "1"},
+		{in: "1e65", wantErr: true},
+		{in: "0.1e-64", wantErr: true},
+		{in: "1.5e-2147483647", wantErr: true},
+		{in: "1.5e2147483647", wantErr: true},
+		{in: "1e99999999999", wantErr: true},
+		{in: "0." + strings.Repeat("1", 65), wantErr: true},
+	}
+	for _, tc := range cases {
+		d, err := ParseDecimal(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseDecimal(%q) error = %v, want error %t", tc.in, err, tc.wantErr)
+			continue
+		}
+		if err == nil && d.String() != tc.want {
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tc.in, d, tc.want)
+		}
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	cases := []struct {
+		in     string
+		places int32
+		want   string
+	}{
+		{"1.005", 2, "1.01"},
+		{"-1.005", 2, "-1.01"},
+		{"1.004", 2, "1.00"},
+		{"2.345", 1, "2.3"},
+		{"2.35", 1, "2.4"},
+		{"12.3", 2, "12.30"},
+		{"1234.5", 0, "1235"},
+		{"-0.4", 0, "0"},
+		{"1.5e3", 2, "1500.00"},
+		{"0", 2, "0.00"},
+	}
+	for _, tc := range cases {
+		got := MustParseDecimal(tc.in).Round(tc.places)
+		if got.String() != tc.want {
+			t.Errorf("%s.Round(%d) = %s, want %s", tc.in, tc.places, got, tc.want)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := MustParseDecimal("0.1"), MustParseDecimal("0.2")
+	if got := a.Add(b); !got.Equal(MustParseDecimal("0.3")) {
+		t.Errorf("0.1 + 0.2 = %s, want 0.3", got)
+	}
+	if got := a.Sub(b); got.String() != "-0.1" {
+		t.Errorf("0.1 - 0.2 = %s, want -0.1", got)
+	}
+	if got := MustParseDecimal("19.99").MulInt(3); got.String() != "59.97" {
+		t.Errorf("19.99 * 3 = %s, want 59.97", got)
+	}
+	if got := MustParseDecimal("1.5").Mul(MustParseDecimal("1.5")); got.String() != "2.25" {
+		t.Errorf("1.5 * 1.5 = %s, want 2.25", got)
+	}
+	if !MustParseDecimal("1.5").Equal(MustParseDecimal("1.50")) {
+		t.Error("1.5 != 1.50")
+	}
+	if (Decimal{}).Cmp(MustParseDecimal("-0.01")) != 1 {
+		t.Error("0 <= -0.01")
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: `"12.30"`, want: "12.30"},
+		{in: `12.30`, want: "12.30"},
+		{in: `12.3`, want: "12.3"},
+		{in: `-5`, want: "-5"},
+		{in: `1e2`, want: "100"},
+		{in: `""`, want: "0"},
+		{in: `" "`, want: "0"},
+		{in: `null`, want: "0"},
+		{in: `"abc"`, wantErr: true},
+		{in: `true`, wantErr: true},
+		{in: `{}`, wantErr: true},
+		{in: `1.5e-2147483647`, wantErr: true},
+	}
+	for _, tc := range cases {
+		var v struct {
+			Price Decimal `json:"price"`
+		}
+		err := json.Unmarshal([]byte(`{"price":`+tc.in+`}`), &v)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("decode %s error = %v, want error %t", tc.in, err, tc.wantErr)
+			continue
+		}
+		if err == nil && v.Price.String() != tc.want {
+			t.Errorf("decode %s = %s, want %s", tc.in, v.Price, tc.want)
+		}
+	}
+
+	out, err := json.Marshal(struct {
+		A Decimal `json:"a"`
+		B Decimal `json:"b"`
+	}{MustParseDecimal("12.30"), Decimal{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a":12.30,"b":0}`; string(out) != want {
+		t.Errorf("encode = %s, want %s", out, want)
+	}
+}
+
+func TestMoney(t *testing.T) {
+	sgd := NewMoney(MustParseDecimal("10.005"), "sgd")
+	if got := sgd.String(); got != "10.01 SGD" {
+		t.Errorf("String = %q, want 10.01 SGD", got)
+	}
+	if got := NewMoney(MustParseDecimal("15000.5"), "IDR").String(); got != "15001 IDR" {
+		t.Errorf("String = %q, want 15001 IDR", got)
+	}
+
+	sum, err := sgd.Add(NewMoney(MustParseDecimal("0.995"), "SGD"))
+	if err != nil || sum.String() != "11.00 SGD" {
+		t.Errorf("Add = %s, %v, want 11.00 SGD", sum, err)
+	}
+	if _, err := sgd.Add(NewMoney(MustParseDecimal("1"), "MYR")); err == nil {
+		t.Error("Add of SGD and MYR succeeded")
+	}
+	// a zero amount of any currency adds up
+	if sum, err := (Money{}).Add(sgd); err != nil || sum.Currency != "SGD" {
+		t.Errorf("Add to zero = %s, %v, want SGD", sum, err)
+	}
+}
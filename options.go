@@ -33,6 +33,67 @@ func WithLogger(logger LeveledLoggerInterface) Option {
 	}
 }
 
+// WithClock sets the clock used to generate request timestamps and default
+// time windows, mostly useful to make tests deterministic.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithClockSkewCompensation makes the client measure the offset between its
+// clock and Shopee's from the Date header of every response, and shift the
+// request timestamps accordingly so a drifting host clock does not get
+// requests rejected.
+func WithClockSkewCompensation() Option {
+	return func(c *Client) {
+		c.skew = &clockSkew{}
+	}
+}
+
+// WithTransport sets the http.RoundTripper used to send requests, e.g. to
+// record and replay them in tests.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.Client.Transport = transport
+	}
+}
+
+// WithRawFields makes the client fill the Raw field of the models it
+// decodes, e.g. Order.Raw, with the JSON received and the fields the model
+// does not declare, so callers can use fields added to the API since this
+// package was released. Without it Raw is always nil. The models carrying
+// it are Shop, Item, Variation, ItemCategory, ItemAttribute, Order,
+// EscrowDetail, Logistic and Discount.
+func WithRawFields() Option {
+	return func(c *Client) {
+		c.rawFields = true
+	}
+}
+
+// WithStrictDecoding makes the client check every response for fields the
+// models do not declare and report them as a warning through the logger,
+// and to onDrift when it is not nil, e.g. to count them in metrics.
+// Responses are still decoded as usual.
+func WithStrictDecoding(onDrift SchemaDriftFunc) Option {
+	return func(c *Client) {
+		c.strict = true
+		c.onDrift = onDrift
+	}
+}
+
+// WithItemLogisticsValidation makes Item.Create and Item.Update check the
+// logistics of the item with ValidateItemLogistics against the channels of
+// the shop before submitting it, at the cost of a call listing them. Items
+// failing validation are not sent and ValidationErrors is returned.
+func WithItemLogisticsValidation() Option {
+	return func(c *Client) {
+		c.validateItemLogistics = true
+	}
+}
+
 func WithProxy(proxyHost string) Option {
 	return func(c *Client) {
 		proxyURL, err := url.Parse(proxyHost)
@@ -1,20 +1,31 @@
 package goshopee
 
 import (
+	"context"
 	"fmt"
-	"time"
+	"iter"
+	"sync"
 )
 
+// OrderService handles the orders of a shop. Its methods send their request
+// as is, the state of the order is not checked: gate them with
+// Order.CheckAction to reject the operations invalid for the order before
+// calling the API.
 type OrderService interface {
 	List(uint64) ([]Order, error)
 	ListWithPagination(sid uint64, offset, limit uint32, options map[string]interface{}) ([]Order, *Pagination, error)
-	Count(interface{}) (int, error)
+	Iter(ctx context.Context, sid uint64, options map[string]interface{}, from Cursor) *Pager[Order]
+	Query(ctx context.Context, sid uint64, query OrderQuery) iter.Seq2[Order, error]
+	Count(ctx context.Context, sid uint64, query OrderQuery) (map[OrderStatus]int, error)
 	Get(sid uint64, ordersn string) (*Order, error)
-	GetMulti(sid uint64, orders []string) ([]Order, []string, error)
-	Create(Order) (*Order, error)
-	Update(Order) (*Order, error)
+	GetMulti(ctx context.Context, sid uint64, orders []string) ([]Order, []string, error)
 	Cancel(sid uint64, ordersn, reason string, options map[string]interface{}) error
-	Delete(int64) error
+	AddNote(sid uint64, ordersn, note string) error
+	SplitOrder(sid uint64, ordersn string, parcels [][]OrderSplitItem) (*OrderSplitResponse, error)
+	UndoSplitOrder(sid uint64, ordersn string) error
+	AcceptBuyerCancellation(sid uint64, ordersn string) error
+	RejectBuyerCancellation(sid uint64, ordersn string) error
+	GetEscrowDetail(sid uint64, ordersn string) (*EscrowDetail, error)
 }
 
 // Order https://open.shopee.com/documents?module=4&type=1&id=397
@@ -22,20 +33,20 @@ type Order struct {
 	OrderSN                      string            `json:"ordersn"`
 	BuyerUsername                string            `json:"buyer_username"`
 	RecipientAddress             *RecipientAddress `json:"recipient_address"`
-	Status                       string            `json:"order_status"`
+	Status                       OrderStatus       `json:"order_status"`
 	Currency                     string            `json:"currency"`
 	TrackingNo                   string            `json:"tracking_no"`
-	EscrowAmount                 string            `json:"escrow_amount"`
-	TotalAmount                  string            `json:"total_amount"`
+	EscrowAmount                 Decimal           `json:"escrow_amount"`
+	TotalAmount                  Decimal           `json:"total_amount"`
 	Country                      string            `json:"country"`
 	ServiceCode                  string            `json:"service_code"`
-	EstimatedShippingFee         string            `json:"estimated_shipping_fee"`
+	EstimatedShippingFee         Decimal           `json:"estimated_shipping_fee"`
 	PaymentMethod                string            `json:"payment_method"`
 	ShippingCarrier              string            `json:"shipping_carrier"`
-	COD                          bool              `json:"cod"`                  // This value indicates whether the order was a COD (cash on delivery) order.
+	COD                          FlexBool          `json:"cod"`                  // This value indicates whether the order was a COD (cash on delivery) order.
 	DaysToShip                   uint32            `json:"days_to_ship"`         // Shipping preparation time set by the seller when listing item on Shopee.
-	ActualShippingCost           string            `json:"actual_shipping_cost"` // The actual shipping cost of the order if available from external logistics partners.
-	GoodsToDeclare               bool              `json:"goods_to_declare"`
+	ActualShippingCost           Decimal           `json:"actual_shipping_cost"` // The actual shipping cost of the order if available from external logistics partners.
+	GoodsToDeclare               FlexBool          `json:"goods_to_declare"`
 	MessageToSeller              string            `json:"message_to_seller"`
 	Note                         string            `json:"note"`
 	NoteUpdateTime               int64             `json:"note_update_time"`
@@ -47,32 +58,35 @@ type Order struct {
 	CreditCardNumber             string            `json:"credit_card_number"`
 	DropShipperPhone             string            `json:"dropshipper_phone"`
 	ShipByDate                   int64             `json:"ship_by_date"`
-	IsSplitUp                    bool              `json:"is_split_up"`
-	BuyerCancelReason            interface{}            `json:"buyer_cancel_reason"` // Cancel order is number, eg. 0, other is string
+	IsSplitUp                    FlexBool          `json:"is_split_up"`
+	BuyerCancelReason            FlexString        `json:"buyer_cancel_reason"` // Cancel order is number, eg. 0, other is string
 	CancelBy                     string            `json:"cancel_by"`
 	FmTN                         string            `json:"fm_tn"` // The first-mile tracking number.
-	CancelReason                 interface{}            `json:"cancel_reason"` // Cancel order is number, eg. 0, other is string
-	EscrowTax                    string            `json:"escrow_tax"`
-	IsActualShippingFeeConfirmed bool              `json:"is_actual_shipping_fee_confirmed"`
+	CancelReason                 FlexString        `json:"cancel_reason"` // Cancel order is number, eg. 0, other is string
+	EscrowTax                    Decimal           `json:"escrow_tax"`
+	IsActualShippingFeeConfirmed FlexBool          `json:"is_actual_shipping_fee_confirmed"`
+
+	// Raw is only set with WithRawFields.
+	Raw *RawFields `json:"-"`
 }
 
 type OrderItem struct {
-	ItemID                   uint64  `json:"item_id"`
-	ItemName                 string  `json:"item_name"`
-	ItemSKU                  string  `json:"item_sku"`
-	VariationID              uint64  `json:"variation_id"`
-	VariationName            string  `json:"variation_name"`
-	VariationSKU             string  `json:"variation_sku"`
-	VariationQuantity        uint32  `json:"variation_quantity_purchased"`
-	VariationDiscountedPrice string  `json:"variation_discounted_price"`
-	VariationOriginalPrice   string  `json:"variation_original_price"`
-	IsWholesale              bool    `json:"is_wholesale"`
-	Weight                   float64 `json:"weight"`
-	IsAddOnDeal              bool    `json:"is_add_on_deal"`
-	IsMainItem               bool    `json:"is_main_item"`
-	AddOnDealID              uint64  `json:"add_on_deal_id"`
-	PromotionType            string  `json:"promotion_type"`
-	PromotionID              uint32  `json:"promotion_id"`
+	ItemID                   uint64   `json:"item_id"`
+	ItemName                 string   `json:"item_name"`
+	ItemSKU                  string   `json:"item_sku"`
+	VariationID              uint64   `json:"variation_id"`
+	VariationName            string   `json:"variation_name"`
+	VariationSKU             string   `json:"variation_sku"`
+	VariationQuantity        uint32   `json:"variation_quantity_purchased"`
+	VariationDiscountedPrice Decimal  `json:"variation_discounted_price"`
+	VariationOriginalPrice   Decimal  `json:"variation_original_price"`
+	IsWholesale              FlexBool `json:"is_wholesale"`
+	Weight                   float64  `json:"weight"`
+	IsAddOnDeal              FlexBool `json:"is_add_on_deal"`
+	IsMainItem               FlexBool `json:"is_main_item"`
+	AddOnDealID              uint64   `json:"add_on_deal_id"`
+	PromotionType            string   `json:"promotion_type"`
+	PromotionID              uint32   `json:"promotion_id"`
 }
 
 type RecipientAddress struct {
@@ -118,7 +132,7 @@ type OrderServiceOp struct {
 
 // List xxx
 func (s *OrderServiceOp) List(sid uint64) ([]Order, error) {
-	timeTo := time.Now().Unix()
+	timeTo := s.client.now().Unix()
 	timeFrom := timeTo - 3600*24*15
 	path := "/orders/basics"
 	wrappedData := map[string]interface{}{
@@ -132,8 +146,56 @@ func (s *OrderServiceOp) List(sid uint64) ([]Order, error) {
 	return resource.Orders, err
 }
 
+// maxOrdersPerPage is the largest page size accepted by the order listings.
+const maxOrdersPerPage = 100
+
+// Iter returns a Pager lazily iterating over the orders matching options,
+// starting at from. The options are the ones of ListWithPagination. When
+// they give neither a create nor an update time range the orders created in
+// the 15 days before Iter is called are listed, that window is kept in the
+// Cursor of the Pager and reused when resuming from it.
+func (s *OrderServiceOp) Iter(ctx context.Context, sid uint64, options map[string]interface{}, from Cursor) *Pager[Order] {
+	opts := map[string]interface{}{}
+	for k, v := range options {
+		opts[k] = v
+	}
+	if hasOrderTimeRange(opts) {
+		from.WindowFrom, from.WindowTo = 0, 0
+	} else {
+		if from.WindowTo == 0 {
+			from.WindowTo = s.client.now().Unix()
+			from.WindowFrom = from.WindowTo - 3600*24*15
+		}
+		opts["create_time_from"] = from.WindowFrom
+		opts["create_time_to"] = from.WindowTo
+	}
+	fetch := func(ctx context.Context, offset uint32) ([]Order, bool, error) {
+		orders, pagination, err := s.listPage(ctx, sid, offset, maxOrdersPerPage, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		return orders, pagination.More, nil
+	}
+	return NewPager(ctx, fetch, from)
+}
+
+// hasOrderTimeRange reports whether options bound the create or update time
+// of the orders listed.
+func hasOrderTimeRange(options map[string]interface{}) bool {
+	for _, opt := range []string{"create_time_from", "create_time_to", "update_time_from", "update_time_to"} {
+		if _, ok := options[opt]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // ListWithPagination https://open.shopee.com/documents?module=4&type=1&id=399
 func (s *OrderServiceOp) ListWithPagination(sid uint64, offset, limit uint32, options map[string]interface{}) ([]Order, *Pagination, error) {
+	return s.listPage(context.Background(), sid, offset, limit, options)
+}
+
+func (s *OrderServiceOp) listPage(ctx context.Context, sid uint64, offset, limit uint32, options map[string]interface{}) ([]Order, *Pagination, error) {
 	path := "/orders/basics"
 
 	wrappedData := map[string]interface{}{
@@ -149,27 +211,50 @@ func (s *OrderServiceOp) ListWithPagination(sid uint64, offset, limit uint32, op
 		wrappedData[opt] = v
 	}
 
-	timeTo := time.Now().Unix()
-	timeFrom := timeTo - 3600*24*15
-
-	if v, ok := wrappedData["create_time_from"]; !ok {
-		wrappedData["create_time_from"] = timeFrom
-	} else {
-		tt := v.(uint32)
-		timeFrom = int64(tt)
+	// time options may be given as any integer type or as time.Time
+	for _, opt := range []string{"create_time_from", "create_time_to", "update_time_from", "update_time_to"} {
+		v, ok := wrappedData[opt]
+		if !ok {
+			continue
+		}
+		t, ok := unixTime(v)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid %s: %v", opt, v)
+		}
+		wrappedData[opt] = t
 	}
 
-	if _, ok := options["create_time_to"]; !ok {
-		wrappedData["create_time_to"] = timeFrom + 3600*24*15
+	// default to orders created in the last 15 days, unless listing by
+	// update time
+	if _, ok := wrappedData["update_time_from"]; !ok {
+		timeTo := s.client.now().Unix()
+		timeFrom := timeTo - 3600*24*15
+
+		if v, ok := wrappedData["create_time_from"]; !ok {
+			wrappedData["create_time_from"] = timeFrom
+		} else {
+			timeFrom = v.(int64)
+		}
+
+		if _, ok := wrappedData["create_time_to"]; !ok {
+			wrappedData["create_time_to"] = timeFrom + 3600*24*15
+		}
 	}
 
 	if v, ok := options["order_status"]; ok {
+		// /orders/get only accepts a create time range
+		if _, ok := wrappedData["update_time_from"]; ok {
+			return nil, nil, fmt.Errorf("order_status can not be used with update_time_from")
+		}
+		if _, ok := wrappedData["update_time_to"]; ok {
+			return nil, nil, fmt.Errorf("order_status can not be used with update_time_to")
+		}
 		path = "/orders/get"
 		wrappedData["order_status"] = v
 	}
 
 	resource := new(OrdersResponse)
-	err := s.client.Post(path, wrappedData, resource)
+	err := s.client.Call(ctx, path, wrappedData, resource)
 	page := &Pagination{
 		Offset:   offset,
 		PageSize: limit,
@@ -178,9 +263,6 @@ func (s *OrderServiceOp) ListWithPagination(sid uint64, offset, limit uint32, op
 	return resource.Orders, page, err
 }
 
-func (s *OrderServiceOp) Count(interface{}) (int, error) {
-	return 0, nil
-}
 func (s *OrderServiceOp) Get(sid uint64, ordersn string) (*Order, error) {
 	path := "/orders/detail"
 	wrappedData := map[string]interface{}{
@@ -195,28 +277,105 @@ func (s *OrderServiceOp) Get(sid uint64, ordersn string) (*Order, error) {
 	return &resource.Orders[0], err
 }
 
-func (s *OrderServiceOp) GetMulti(sid uint64, orders []string) ([]Order, []string, error) {
+const (
+	// maxOrdersPerDetail is the largest ordersn_list accepted by /orders/detail.
+	maxOrdersPerDetail = 50
+
+	// getMultiConcurrency is the number of /orders/detail calls GetMulti
+	// runs at once.
+	getMultiConcurrency = 4
+)
+
+// GetMulti https://open.shopee.com/documents?module=4&type=1&id=397
+// The orders are fetched in chunks of 50 running concurrently and returned
+// in the order of the given ordersn, along with the ordersn which were not
+// found. An error is only returned when a call fails, no chunk is sent after
+// that or once ctx is done.
+func (s *OrderServiceOp) GetMulti(ctx context.Context, sid uint64, orders []string) ([]Order, []string, error) {
+	var chunks [][]string
+	for start := 0; start < len(orders); start += maxOrdersPerDetail {
+		end := start + maxOrdersPerDetail
+		if end > len(orders) {
+			end = len(orders)
+		}
+		chunks = append(chunks, orders[start:end])
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		found    = make(map[string]Order, len(orders))
+		sem      = make(chan struct{}, getMultiConcurrency)
+	)
+dispatch:
+	for _, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+		// a failed chunk cancels ctx before freeing its slot
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(chunk []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			resource, err := s.getDetail(ctx, sid, chunk)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			for _, order := range resource.Orders {
+				found[order.OrderSN] = order
+			}
+		}(chunk)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]Order, 0, len(found))
+	var notFound []string
+	seen := make(map[string]bool, len(orders))
+	for _, sn := range orders {
+		if seen[sn] {
+			continue
+		}
+		seen[sn] = true
+		if order, ok := found[sn]; ok {
+			result = append(result, order)
+		} else {
+			notFound = append(notFound, sn)
+		}
+	}
+	return result, notFound, nil
+}
+
+func (s *OrderServiceOp) getDetail(ctx context.Context, sid uint64, orders []string) (*OrdersDetailResponse, error) {
 	path := "/orders/detail"
 	wrappedData := map[string]interface{}{
 		"ordersn_list": orders,
 		"shopid":       sid,
 	}
 	resource := new(OrdersDetailResponse)
-	err := s.client.Post(path, wrappedData, resource)
-	if len(resource.Orders) == 0 {
-		return nil, resource.Errors, fmt.Errorf("no such order: [%v] %s", orders, err)
-	}
-	return resource.Orders, resource.Errors, err
-}
-
-// Create https://open.shopee.com/documents?module=2&type=1&id=365
-func (s *OrderServiceOp) Create(Order) (*Order, error) {
-	return nil, nil
-}
-
-// Update https://open.shopee.com/documents?module=2&type=1&id=376
-func (s *OrderServiceOp) Update(Order) (*Order, error) {
-	return nil, nil
+	err := s.client.Call(ctx, path, wrappedData, resource)
+	return resource, err
 }
 
 type OrderCancelResponse struct {
@@ -242,7 +401,3 @@ func (s *OrderServiceOp) Cancel(sid uint64, ordersn, reason string, options map[
 	err := s.client.Post(path, wrappedData, resource)
 	return err
 }
-
-func (s *OrderServiceOp) Delete(int64) error {
-	return nil
-}
@@ -0,0 +1,197 @@
+package goshopee
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errEmptyOrderSN = errors.New("ordersn is required")
+
+type OrderActionResponse struct {
+	OrderSN      string `json:"ordersn"`
+	ModifiedTime uint32 `json:"modified_time"`
+	RequestID    string `json:"request_id"`
+}
+
+// AddNote wraps shopee.orders.AddOrderNote, it sets the seller note of the order, replacing the previous one.
+func (s *OrderServiceOp) AddNote(sid uint64, ordersn, note string) error {
+	if ordersn == "" {
+		return errEmptyOrderSN
+	}
+	path := "/orders/note/add"
+	wrappedData := map[string]interface{}{
+		"ordersn": ordersn,
+		"note":    note,
+		"shopid":  sid,
+	}
+	resource := new(OrderActionResponse)
+	return s.client.Post(path, wrappedData, resource)
+}
+
+// OrderSplitItem is an item put in a parcel when splitting an order.
+type OrderSplitItem struct {
+	ItemID      uint64 `json:"item_id"`
+	VariationID uint64 `json:"variation_id"`
+}
+
+type OrderParcel struct {
+	ForderID string           `json:"forder_id"`
+	Items    []OrderSplitItem `json:"items"`
+}
+
+type OrderSplitResponse struct {
+	OrderSN   string        `json:"ordersn"`
+	Parcels   []OrderParcel `json:"parcels"`
+	RequestID string        `json:"request_id"`
+}
+
+// SplitOrder wraps shopee.orders.SplitOrder. Every parcel lists the items shipped together, an order is split in at
+// least two parcels.
+func (s *OrderServiceOp) SplitOrder(sid uint64, ordersn string, parcels [][]OrderSplitItem) (*OrderSplitResponse, error) {
+	if ordersn == "" {
+		return nil, errEmptyOrderSN
+	}
+	if len(parcels) < 2 {
+		return nil, errors.New("an order is split in at least 2 parcels")
+	}
+	for _, parcel := range parcels {
+		if len(parcel) == 0 {
+			return nil, errors.New("every parcel needs at least one item")
+		}
+	}
+	path := "/orders/split"
+	wrappedData := map[string]interface{}{
+		"ordersn": ordersn,
+		"items":   parcels,
+		"shopid":  sid,
+	}
+	resource := new(OrderSplitResponse)
+	err := s.client.Post(path, wrappedData, resource)
+	return resource, err
+}
+
+// UndoSplitOrder wraps shopee.orders.UndoSplitOrder
+func (s *OrderServiceOp) UndoSplitOrder(sid uint64, ordersn string) error {
+	return s.orderAction("/orders/unsplit", sid, ordersn)
+}
+
+// AcceptBuyerCancellation wraps shopee.orders.AcceptBuyerCancellation
+func (s *OrderServiceOp) AcceptBuyerCancellation(sid uint64, ordersn string) error {
+	return s.orderAction("/orders/buyer_cancellation/accept", sid, ordersn)
+}
+
+// RejectBuyerCancellation wraps shopee.orders.RejectBuyerCancellation
+func (s *OrderServiceOp) RejectBuyerCancellation(sid uint64, ordersn string) error {
+	return s.orderAction("/orders/buyer_cancellation/reject", sid, ordersn)
+}
+
+func (s *OrderServiceOp) orderAction(path string, sid uint64, ordersn string) error {
+	if ordersn == "" {
+		return errEmptyOrderSN
+	}
+	wrappedData := map[string]interface{}{
+		"ordersn": ordersn,
+		"shopid":  sid,
+	}
+	resource := new(OrderActionResponse)
+	return s.client.Post(path, wrappedData, resource)
+}
+
+// EscrowDetail is the income of an order, from shopee.orders.GetEscrowDetails
+type EscrowDetail struct {
+	OrderSN       string              `json:"ordersn"`
+	BuyerUserName string              `json:"buyer_user_name"`
+	ReturnSNList  []string            `json:"returnsn_list"`
+	IncomeDetails EscrowIncomeDetails `json:"income_details"`
+	Activity      []EscrowActivity    `json:"activity"`
+	Items         []EscrowItem        `json:"items"`
+	BankAccount   *EscrowBankAccount  `json:"bank_account,omitempty"`
+	Adjustments   []EscrowAdjustment  `json:"adjustments,omitempty"`
+
+	// Raw is only set with WithRawFields.
+	Raw *RawFields `json:"-"`
+}
+
+type EscrowIncomeDetails struct {
+	LocalCurrency            string  `json:"local_currency"`
+	TotalAmount              Decimal `json:"total_amount"`
+	Coin                     Decimal `json:"coin"`
+	Voucher                  Decimal `json:"voucher"`
+	VoucherSeller            Decimal `json:"voucher_seller"`
+	SellerRebate             Decimal `json:"seller_rebate"`
+	ActualShippingCost       Decimal `json:"actual_shipping_cost"`
+	ShippingFeeRebate        Decimal `json:"shipping_fee_rebate"`
+	CommissionFee            Decimal `json:"commission_fee"`
+	VoucherCode              string  `json:"voucher_code"`
+	VoucherName              string  `json:"voucher_name"`
+	EscrowAmount             Decimal `json:"escrow_amount"`
+	CrossBorderTax           Decimal `json:"cross_border_tax"`
+	CreditCardTransactionFee Decimal `json:"credit_card_transaction_fee"`
+	ServiceFee               Decimal `json:"service_fee"`
+	BuyerTransactionFee      Decimal `json:"buyer_transaction_fee"`
+}
+
+type EscrowActivity struct {
+	ActivityID      uint64       `json:"activity_id"`
+	ActivityType    string       `json:"activity_type"`
+	OriginalPrice   Decimal      `json:"original_price"`
+	DiscountedPrice Decimal      `json:"discounted_price"`
+	Items           []EscrowItem `json:"items"`
+}
+
+type EscrowItem struct {
+	ItemID                    uint64   `json:"item_id"`
+	Name                      string   `json:"name"`
+	ItemSKU                   string   `json:"item_sku"`
+	VariationID               uint64   `json:"variation_id"`
+	VariationName             string   `json:"variation_name"`
+	VariationSKU              string   `json:"variation_sku"`
+	QuantityPurchased         uint32   `json:"quantity_purchased"`
+	OriginalPrice             Decimal  `json:"original_price"`
+	DealPrice                 Decimal  `json:"deal_price"`
+	DiscountedPrice           Decimal  `json:"discounted_price"`
+	DiscountFromCoin          Decimal  `json:"discount_from_coin"`
+	DiscountFromVoucher       Decimal  `json:"discount_from_voucher"`
+	DiscountFromVoucherSeller Decimal  `json:"discount_from_voucher_seller"`
+	SellerRebate              Decimal  `json:"seller_rebate"`
+	IsAddOnDeal               FlexBool `json:"is_add_on_deal"`
+	IsMainItem                FlexBool `json:"is_main_item"`
+	AddOnDealID               uint64   `json:"add_on_deal_id"`
+}
+
+type EscrowBankAccount struct {
+	BankName           string `json:"bank_name"`
+	BankAccountNumber  string `json:"bank_account_number"`
+	BankAccountCountry string `json:"bank_account_country"`
+}
+
+type EscrowAdjustment struct {
+	Reason string  `json:"reason"`
+	Amount Decimal `json:"amount"`
+}
+
+type EscrowDetailResponse struct {
+	Order     *EscrowDetail `json:"order"`
+	RequestID string        `json:"request_id"`
+}
+
+// GetEscrowDetail wraps shopee.orders.GetEscrowDetails
+func (s *OrderServiceOp) GetEscrowDetail(sid uint64, ordersn string) (*EscrowDetail, error) {
+	if ordersn == "" {
+		return nil, errEmptyOrderSN
+	}
+	path := "/orders/my_income"
+	wrappedData := map[string]interface{}{
+		"ordersn": ordersn,
+		"shopid":  sid,
+	}
+	resource := new(EscrowDetailResponse)
+	err := s.client.Post(path, wrappedData, resource)
+	if err != nil {
+		return nil, err
+	}
+	if resource.Order == nil {
+		return nil, fmt.Errorf("no escrow detail for order: %s", ordersn)
+	}
+	return resource.Order, nil
+}
@@ -0,0 +1,211 @@
+package goshopee_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+)
+
+// requestParams returns the body of a request without the envelope fields
+// every call carries.
+func requestParams(t *testing.T, r goshopeetest.Request) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(r.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	delete(body, "partner_id")
+	delete(body, "timestamp")
+	return body
+}
+
+func TestOrderManagePayloads(t *testing.T) {
+	cases := []struct {
+		name   string
+		status goshopee.OrderStatus
+		split  bool
+		call   func(s goshopee.OrderService) error
+		path   string
+		want   map[string]interface{}
+	}{
+		{
+			name: "add note",
+			call: func(s goshopee.OrderService) error { return s.AddNote(7, "SN1", "fragile") },
+			path: "/orders/note/add",
+			want: map[string]interface{}{"shopid": float64(7), "ordersn": "SN1", "note": "fragile"},
+		},
+		{
+			name: "split",
+			call: func(s goshopee.OrderService) error {
+				_, err := s.SplitOrder(7, "SN1", [][]goshopee.OrderSplitItem{
+					{{ItemID: 1, VariationID: 10}},
+					{{ItemID: 2}, {ItemID: 3, VariationID: 30}},
+				})
+				return err
+			},
+			path: "/orders/split",
+			want: map[string]interface{}{
+				"shopid":  float64(7),
+				"ordersn": "SN1",
+				"items": []interface{}{
+					[]interface{}{
+						map[string]interface{}{"item_id": float64(1), "variation_id": float64(10)},
+					},
+					[]interface{}{
+						map[string]interface{}{"item_id": float64(2), "variation_id": float64(0)},
+						map[string]interface{}{"item_id": float64(3), "variation_id": float64(30)},
+					},
+				},
+			},
+		},
+		{
+			name:  "undo split",
+			split: true,
+			call:  func(s goshopee.OrderService) error { return s.UndoSplitOrder(7, "SN1") },
+			path:  "/orders/unsplit",
+			want:  map[string]interface{}{"shopid": float64(7), "ordersn": "SN1"},
+		},
+		{
+			name:   "accept buyer cancellation",
+			status: goshopee.OrderStatusInCancel,
+			call:   func(s goshopee.OrderService) error { return s.AcceptBuyerCancellation(7, "SN1") },
+			path:   "/orders/buyer_cancellation/accept",
+			want:   map[string]interface{}{"shopid": float64(7), "ordersn": "SN1"},
+		},
+		{
+			name:   "reject buyer cancellation",
+			status: goshopee.OrderStatusInCancel,
+			call:   func(s goshopee.OrderService) error { return s.RejectBuyerCancellation(7, "SN1") },
+			path:   "/orders/buyer_cancellation/reject",
+			want:   map[string]interface{}{"shopid": float64(7), "ordersn": "SN1"},
+		},
+		{
+			name: "escrow detail",
+			call: func(s goshopee.OrderService) error {
+				_, err := s.GetEscrowDetail(7, "SN1")
+				return err
+			},
+			path: "/orders/my_income",
+			want: map[string]interface{}{"shopid": float64(7), "ordersn": "SN1"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			srv.AddOrder(7, goshopee.Order{OrderSN: "SN1", Status: tc.status, IsSplitUp: goshopee.FlexBool(tc.split)})
+
+			if err := tc.call(srv.Client().Order); err != nil {
+				t.Fatalf("call: %v", err)
+			}
+			reqs := srv.Requests()
+			if len(reqs) != 1 || reqs[0].Path != tc.path {
+				t.Fatalf("Requests = %+v, want a single call to %s", reqs, tc.path)
+			}
+			if got := requestParams(t, reqs[0]); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("params = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOrderManageResults(t *testing.T) {
+	srv := goshopeetest.NewServer(1, "key")
+	defer srv.Close()
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN1", Currency: "SGD", TotalAmount: goshopee.MustParseDecimal("12.30")})
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN2", Status: goshopee.OrderStatusInCancel})
+	orders := srv.Client().Order
+
+	if err := orders.AddNote(7, "SN1", "fragile"); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+	if o, _ := srv.Order(7, "SN1"); o.Note != "fragile" {
+		t.Errorf("note = %q, want fragile", o.Note)
+	}
+
+	split, err := orders.SplitOrder(7, "SN1", [][]goshopee.OrderSplitItem{{{ItemID: 1}}, {{ItemID: 2}}})
+	if err != nil {
+		t.Fatalf("SplitOrder: %v", err)
+	}
+	if split.OrderSN != "SN1" || len(split.Parcels) != 2 || split.Parcels[1].Items[0].ItemID != 2 {
+		t.Errorf("SplitOrder = %+v, want 2 parcels of SN1", split)
+	}
+
+	escrow, err := orders.GetEscrowDetail(7, "SN1")
+	if err != nil {
+		t.Fatalf("GetEscrowDetail: %v", err)
+	}
+	if escrow.OrderSN != "SN1" || escrow.IncomeDetails.TotalAmount.String() != "12.30" {
+		t.Errorf("GetEscrowDetail = %+v, want SN1 with a total of 12.30", escrow)
+	}
+
+	if err := orders.AcceptBuyerCancellation(7, "SN2"); err != nil {
+		t.Fatalf("AcceptBuyerCancellation: %v", err)
+	}
+	if o, _ := srv.Order(7, "SN2"); o.Status != goshopee.OrderStatusCancelled {
+		t.Errorf("status = %s, want CANCELLED", o.Status)
+	}
+}
+
+func TestOrderManageValidation(t *testing.T) {
+	one := []goshopee.OrderSplitItem{{ItemID: 1}}
+	cases := []struct {
+		name    string
+		call    func(s goshopee.OrderService) error
+		wantErr string
+	}{
+		{"add note", func(s goshopee.OrderService) error { return s.AddNote(7, "", "note") }, "ordersn is required"},
+		{"split", func(s goshopee.OrderService) error {
+			_, err := s.SplitOrder(7, "", [][]goshopee.OrderSplitItem{one, one})
+			return err
+		}, "ordersn is required"},
+		{"undo split", func(s goshopee.OrderService) error { return s.UndoSplitOrder(7, "") }, "ordersn is required"},
+		{"accept", func(s goshopee.OrderService) error { return s.AcceptBuyerCancellation(7, "") }, "ordersn is required"},
+		{"reject", func(s goshopee.OrderService) error { return s.RejectBuyerCancellation(7, "") }, "ordersn is required"},
+		{"escrow", func(s goshopee.OrderService) error {
+			_, err := s.GetEscrowDetail(7, "")
+			return err
+		}, "ordersn is required"},
+		{"split in one parcel", func(s goshopee.OrderService) error {
+			_, err := s.SplitOrder(7, "SN1", [][]goshopee.OrderSplitItem{one})
+			return err
+		}, "an order is split in at least 2 parcels"},
+		{"split with an empty parcel", func(s goshopee.OrderService) error {
+			_, err := s.SplitOrder(7, "SN1", [][]goshopee.OrderSplitItem{one, {}})
+			return err
+		}, "every parcel needs at least one item"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			srv.AddOrder(7, goshopee.Order{OrderSN: "SN1"})
+
+			err := tc.call(srv.Client().Order)
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("error = %v, want %q", err, tc.wantErr)
+			}
+			if n := len(srv.Requests()); n != 0 {
+				t.Errorf("sent %d requests, want none", n)
+			}
+		})
+	}
+}
+
+func TestGetEscrowDetailMissing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"request_id": "r1"}`))
+	}))
+	defer srv.Close()
+	client := goshopee.NewClient(goshopee.App{PartnerID: 1, PartnerKey: "key", APIURL: srv.URL})
+
+	escrow, err := client.Order.GetEscrowDetail(7, "SN1")
+	if err == nil || err.Error() != "no escrow detail for order: SN1" {
+		t.Errorf("GetEscrowDetail = %+v, %v, want a missing escrow error", escrow, err)
+	}
+}
@@ -0,0 +1,252 @@
+package goshopee
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"sync"
+	"time"
+)
+
+// maxOrderTimeRange is the longest create or update time range the order
+// listings accept.
+const maxOrderTimeRange = 15 * 24 * time.Hour
+
+// OrderTimeField is the order time an OrderQuery range applies to.
+type OrderTimeField string
+
+const (
+	OrderCreateTime OrderTimeField = "create_time"
+	OrderUpdateTime OrderTimeField = "update_time"
+)
+
+// OrderQuery selects the orders returned by OrderService.Query. The time
+// range may be of any length, it is split in windows Shopee accepts.
+type OrderQuery struct {
+	// TimeField is the time From and To apply to, defaults to OrderCreateTime.
+	TimeField OrderTimeField
+
+	// From and To bound the time range, both inclusive. To defaults to now
+	// and From to 15 days before To.
+	From time.Time
+	To   time.Time
+
+	// Status only returns the orders with that status, e.g. READY_TO_SHIP.
+	// Shopee only lists orders by status and create time, so it can not be
+	// used with OrderUpdateTime.
+	Status OrderStatus
+
+	// PageSize is the number of orders fetched per call, at most and by
+	// default 100.
+	PageSize uint32
+}
+
+// timeWindow is a time range in unix seconds, both ends inclusive.
+type timeWindow struct {
+	From int64
+	To   int64
+}
+
+// splitTimeRange splits [from, to] in consecutive windows no longer than max.
+func splitTimeRange(from, to int64, max time.Duration) []timeWindow {
+	step := int64(max / time.Second)
+	var windows []timeWindow
+	for start := from; start <= to; start += step + 1 {
+		end := start + step
+		if end > to {
+			end = to
+		}
+		windows = append(windows, timeWindow{From: start, To: end})
+	}
+	return windows
+}
+
+// windows returns the time windows covered by the query, or an error when
+// its range is inverted or it can not be listed.
+func (q OrderQuery) windows(now time.Time) ([]timeWindow, error) {
+	if q.Status != "" && q.TimeField == OrderUpdateTime {
+		return nil, fmt.Errorf("order query by status %s only supports the create time", q.Status)
+	}
+	to := q.To
+	if to.IsZero() {
+		to = now
+	}
+	from := q.From
+	if from.IsZero() {
+		from = to.Add(-maxOrderTimeRange)
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("order query from %s is after to %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return splitTimeRange(from.Unix(), to.Unix(), maxOrderTimeRange), nil
+}
+
+// options returns the listing options of the query for a window.
+func (q OrderQuery) options(w timeWindow) map[string]interface{} {
+	field := q.TimeField
+	if field == "" {
+		field = OrderCreateTime
+	}
+	opts := map[string]interface{}{
+		string(field) + "_from": w.From,
+		string(field) + "_to":   w.To,
+	}
+	if q.Status != "" {
+		opts["order_status"] = q.Status
+	}
+	return opts
+}
+
+// Query returns an iterator over the orders matching query. The time range
+// is split in 15 days windows listed from the oldest to the newest, every
+// window is paginated and an order is only yielded once even when it shows
+// up in several pages or windows. Iteration stops at the first error.
+func (s *OrderServiceOp) Query(ctx context.Context, sid uint64, query OrderQuery) iter.Seq2[Order, error] {
+	return func(yield func(Order, error) bool) {
+		pageSize := query.PageSize
+		if pageSize == 0 || pageSize > maxOrdersPerPage {
+			pageSize = maxOrdersPerPage
+		}
+		windows, err := query.windows(s.client.now())
+		if err != nil {
+			yield(Order{}, err)
+			return
+		}
+		seen := map[string]bool{}
+		for _, w := range windows {
+			opts := query.options(w)
+			fetch := func(ctx context.Context, offset uint32) ([]Order, bool, error) {
+				orders, pagination, err := s.listPage(ctx, sid, offset, pageSize, opts)
+				if err != nil {
+					return nil, false, err
+				}
+				return orders, pagination.More, nil
+			}
+			for order, err := range NewPager(ctx, fetch, Cursor{}).All() {
+				if err != nil {
+					yield(Order{}, err)
+					return
+				}
+				if seen[order.OrderSN] {
+					continue
+				}
+				seen[order.OrderSN] = true
+				if !yield(order, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// countConcurrency is the number of time windows Count walks at once.
+const countConcurrency = 4
+
+// Count returns the number of orders matching query per order status, e.g.
+// {"READY_TO_SHIP": 12, "COMPLETED": 80}. Only the order listings are
+// walked, the time windows of the query concurrently.
+func (s *OrderServiceOp) Count(ctx context.Context, sid uint64, query OrderQuery) (map[OrderStatus]int, error) {
+	windows, err := query.windows(s.client.now())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		counts   = map[OrderStatus]int{}
+		sem      = make(chan struct{}, countConcurrency)
+	)
+	for _, w := range windows {
+		wg.Add(1)
+		go func(opts map[string]interface{}) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			windowCounts, err := s.countWindow(ctx, sid, opts)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			for status, n := range windowCounts {
+				counts[status] += n
+			}
+		}(query.options(w))
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// countWindow counts the orders of a single time window per status.
+func (s *OrderServiceOp) countWindow(ctx context.Context, sid uint64, opts map[string]interface{}) (map[OrderStatus]int, error) {
+	counts := map[OrderStatus]int{}
+	seen := map[string]bool{}
+	var offset uint32
+	for {
+		orders, pagination, err := s.listPage(ctx, sid, offset, maxOrdersPerPage, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range orders {
+			if !seen[order.OrderSN] {
+				seen[order.OrderSN] = true
+				counts[order.Status]++
+			}
+		}
+		if !pagination.More || len(orders) == 0 {
+			return counts, nil
+		}
+		offset += uint32(len(orders))
+	}
+}
+
+// unixTime converts a time option to unix seconds.
+func unixTime(v interface{}) (int64, bool) {
+	switch t := v.(type) {
+	case int:
+		return int64(t), true
+	case int32:
+		return int64(t), true
+	case int64:
+		return t, true
+	case uint:
+		return int64(t), true
+	case uint32:
+		return int64(t), true
+	case uint64:
+		return int64(t), true
+	case float64:
+		return int64(t), true
+	case json.Number:
+		n, err := t.Int64()
+		return n, err == nil
+	case time.Time:
+		return t.Unix(), true
+	case *time.Time:
+		if t == nil {
+			return 0, false
+		}
+		return t.Unix(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,113 @@
+package goshopee
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitTimeRange(t *testing.T) {
+	const day = 24 * 3600
+	cases := []struct {
+		name     string
+		from, to int64
+		want     []timeWindow
+	}{
+		{"instant", 100, 100, []timeWindow{{100, 100}}},
+		{"short", 0, day, []timeWindow{{0, day}}},
+		{"exactly 15 days", 0, 15 * day, []timeWindow{{0, 15 * day}}},
+		{"15 days and a second", 0, 15*day + 1, []timeWindow{{0, 15 * day}, {15*day + 1, 15*day + 1}}},
+		{"30 days and a second", 0, 30*day + 1, []timeWindow{{0, 15 * day}, {15*day + 1, 30*day + 1}}},
+		{"40 days", 0, 40 * day, []timeWindow{{0, 15 * day}, {15*day + 1, 30*day + 1}, {30*day + 2, 40 * day}}},
+		{"inverted", 15 * day, 0, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := splitTimeRange(tc.from, tc.to, maxOrderTimeRange)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("splitTimeRange(%d, %d) = %v, want %v", tc.from, tc.to, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOrderQueryWindows(t *testing.T) {
+	now := time.Unix(100*24*3600, 0)
+	cases := []struct {
+		name    string
+		query   OrderQuery
+		want    []timeWindow
+		wantErr bool
+	}{
+		{
+			name:  "defaults to the last 15 days",
+			query: OrderQuery{},
+			want:  []timeWindow{{now.Unix() - 15*24*3600, now.Unix()}},
+		},
+		{
+			name:  "from defaults to 15 days before to",
+			query: OrderQuery{To: now.Add(-time.Hour)},
+			want:  []timeWindow{{now.Unix() - 15*24*3600 - 3600, now.Unix() - 3600}},
+		},
+		{
+			name:  "to defaults to now",
+			query: OrderQuery{From: now.Add(-16 * 24 * time.Hour)},
+			want:  []timeWindow{{now.Unix() - 16*24*3600, now.Unix() - 24*3600}, {now.Unix() - 24*3600 + 1, now.Unix()}},
+		},
+		{
+			name:    "inverted",
+			query:   OrderQuery{From: now, To: now.Add(-time.Second)},
+			wantErr: true,
+		},
+		{
+			name:    "from in the future",
+			query:   OrderQuery{From: now.Add(time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:  "status by create time",
+			query: OrderQuery{Status: OrderStatusReadyToShip},
+			want:  []timeWindow{{now.Unix() - 15*24*3600, now.Unix()}},
+		},
+		{
+			name:    "status by update time",
+			query:   OrderQuery{TimeField: OrderUpdateTime, Status: OrderStatusReadyToShip},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.query.windows(now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("windows error = %v, want error %t", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("windows = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOrderQueryInverted(t *testing.T) {
+	// the range is checked before any request is sent
+	c := NewClient(App{APIURL: "http://127.0.0.1:1"})
+	now := time.Now()
+	query := OrderQuery{From: now, To: now.Add(-time.Hour)}
+
+	n := 0
+	for _, err := range c.Order.Query(context.Background(), 7, query) {
+		n++
+		if err == nil {
+			t.Fatal("Query yielded an order, want an error")
+		}
+	}
+	if n != 1 {
+		t.Errorf("Query yielded %d times, want once", n)
+	}
+
+	counts, err := c.Order.Count(context.Background(), 7, query)
+	if err == nil {
+		t.Errorf("Count = %v, want an error", counts)
+	}
+}
@@ -0,0 +1,145 @@
+package goshopee
+
+import (
+	"fmt"
+	"sort"
+)
+
+// OrderStatus is the status of an order.
+type OrderStatus string
+
+const (
+	OrderStatusUnpaid           OrderStatus = "UNPAID"
+	OrderStatusReadyToShip      OrderStatus = "READY_TO_SHIP"
+	OrderStatusRetryShip        OrderStatus = "RETRY_SHIP"
+	OrderStatusShipped          OrderStatus = "SHIPPED"
+	OrderStatusToConfirmReceive OrderStatus = "TO_CONFIRM_RECEIVE"
+	OrderStatusInCancel         OrderStatus = "IN_CANCEL"
+	OrderStatusCancelled        OrderStatus = "CANCELLED"
+	OrderStatusToReturn         OrderStatus = "TO_RETURN"
+	OrderStatusCompleted        OrderStatus = "COMPLETED"
+)
+
+// OrderStatuses lists every order status, in the order an order goes
+// through them.
+var OrderStatuses = []OrderStatus{
+	OrderStatusUnpaid,
+	OrderStatusReadyToShip,
+	OrderStatusRetryShip,
+	OrderStatusShipped,
+	OrderStatusToConfirmReceive,
+	OrderStatusInCancel,
+	OrderStatusCancelled,
+	OrderStatusToReturn,
+	OrderStatusCompleted,
+}
+
+// IsValid reports whether s is a known order status.
+func (s OrderStatus) IsValid() bool {
+	for _, status := range OrderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether an order with that status will not change anymore.
+func (s OrderStatus) IsFinal() bool {
+	return s == OrderStatusCompleted || s == OrderStatusCancelled
+}
+
+// OrderAction is an operation a seller can apply to an order.
+type OrderAction string
+
+const (
+	OrderActionCancel                  OrderAction = "cancel"
+	OrderActionInitLogistics           OrderAction = "init_logistics"
+	OrderActionAcceptBuyerCancellation OrderAction = "accept_buyer_cancellation"
+	OrderActionRejectBuyerCancellation OrderAction = "reject_buyer_cancellation"
+	OrderActionSplit                   OrderAction = "split"
+	OrderActionUndoSplit               OrderAction = "undo_split"
+	OrderActionAddNote                 OrderAction = "add_note"
+)
+
+// OrderTransitions lists, per status, the actions which are valid and the
+// status the order is expected to have once the action succeeded.
+var OrderTransitions = map[OrderStatus]map[OrderAction]OrderStatus{
+	OrderStatusUnpaid: {
+		OrderActionCancel:  OrderStatusCancelled,
+		OrderActionAddNote: OrderStatusUnpaid,
+	},
+	OrderStatusReadyToShip: {
+		OrderActionCancel:        OrderStatusCancelled,
+		OrderActionInitLogistics: OrderStatusReadyToShip, // until the parcel is picked up
+		OrderActionSplit:         OrderStatusReadyToShip,
+		OrderActionUndoSplit:     OrderStatusReadyToShip,
+		OrderActionAddNote:       OrderStatusReadyToShip,
+	},
+	OrderStatusRetryShip: {
+		OrderActionInitLogistics: OrderStatusRetryShip,
+		OrderActionAddNote:       OrderStatusRetryShip,
+	},
+	OrderStatusInCancel: {
+		OrderActionAcceptBuyerCancellation: OrderStatusCancelled,
+		OrderActionRejectBuyerCancellation: OrderStatusReadyToShip,
+		OrderActionAddNote:                 OrderStatusInCancel,
+	},
+	OrderStatusShipped:          {OrderActionAddNote: OrderStatusShipped},
+	OrderStatusToConfirmReceive: {OrderActionAddNote: OrderStatusToConfirmReceive},
+	OrderStatusCancelled:        {OrderActionAddNote: OrderStatusCancelled},
+	OrderStatusToReturn:         {OrderActionAddNote: OrderStatusToReturn},
+	OrderStatusCompleted:        {OrderActionAddNote: OrderStatusCompleted},
+}
+
+// InvalidOrderActionError is returned when an action is not valid for the
+// current state of an order.
+type InvalidOrderActionError struct {
+	OrderSN string
+	Status  OrderStatus
+	Action  OrderAction
+	Reason  string
+}
+
+func (e InvalidOrderActionError) Error() string {
+	msg := fmt.Sprintf("order %s: %s is not allowed in status %s", e.OrderSN, e.Action, e.Status)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// CheckAction returns an InvalidOrderActionError when action can not be
+// applied to the order in its current state, so invalid operations are
+// rejected before calling the API. The OrderService methods do not call it,
+// e.g. check OrderActionCancel before OrderService.Cancel.
+func (o *Order) CheckAction(action OrderAction) error {
+	if _, ok := OrderTransitions[o.Status][action]; !ok {
+		return InvalidOrderActionError{OrderSN: o.OrderSN, Status: o.Status, Action: action}
+	}
+	switch {
+	case action == OrderActionSplit && bool(o.IsSplitUp):
+		return InvalidOrderActionError{OrderSN: o.OrderSN, Status: o.Status, Action: action, Reason: "already split"}
+	case action == OrderActionUndoSplit && !bool(o.IsSplitUp):
+		return InvalidOrderActionError{OrderSN: o.OrderSN, Status: o.Status, Action: action, Reason: "not split"}
+	}
+	return nil
+}
+
+// Can reports whether action can be applied to the order.
+func (o *Order) Can(action OrderAction) bool {
+	return o.CheckAction(action) == nil
+}
+
+// NextActions returns the actions which can be applied to the order, sorted
+// by name.
+func (o *Order) NextActions() []OrderAction {
+	var actions []OrderAction
+	for action := range OrderTransitions[o.Status] {
+		if o.Can(action) {
+			actions = append(actions, action)
+		}
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+	return actions
+}
@@ -0,0 +1,128 @@
+package goshopee
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderTransitions(t *testing.T) {
+	for _, status := range OrderStatuses {
+		actions, ok := OrderTransitions[status]
+		if !ok {
+			t.Errorf("no transitions for %s", status)
+			continue
+		}
+		if _, ok := actions[OrderActionAddNote]; !ok {
+			t.Errorf("%s does not allow adding a note", status)
+		}
+		for action, next := range actions {
+			if !next.IsValid() {
+				t.Errorf("%s %s leads to unknown status %q", status, action, next)
+			}
+		}
+		if status.IsFinal() && len(actions) != 1 {
+			t.Errorf("final status %s allows %v, want only %s", status, actions, OrderActionAddNote)
+		}
+	}
+	for status := range OrderTransitions {
+		if !status.IsValid() {
+			t.Errorf("transitions for unknown status %q", status)
+		}
+	}
+
+	cases := []struct {
+		status OrderStatus
+		action OrderAction
+		want   OrderStatus
+	}{
+		{OrderStatusUnpaid, OrderActionCancel, OrderStatusCancelled},
+		{OrderStatusReadyToShip, OrderActionCancel, OrderStatusCancelled},
+		{OrderStatusReadyToShip, OrderActionInitLogistics, OrderStatusReadyToShip},
+		{OrderStatusRetryShip, OrderActionInitLogistics, OrderStatusRetryShip},
+		{OrderStatusInCancel, OrderActionAcceptBuyerCancellation, OrderStatusCancelled},
+		{OrderStatusInCancel, OrderActionRejectBuyerCancellation, OrderStatusReadyToShip},
+	}
+	for _, tc := range cases {
+		if got := OrderTransitions[tc.status][tc.action]; got != tc.want {
+			t.Errorf("%s %s leads to %q, want %s", tc.status, tc.action, got, tc.want)
+		}
+	}
+}
+
+func TestOrderCheckAction(t *testing.T) {
+	cases := []struct {
+		name       string
+		status     OrderStatus
+		split      bool
+		action     OrderAction
+		wantErr    bool
+		wantReason string
+	}{
+		{"cancel unpaid", OrderStatusUnpaid, false, OrderActionCancel, false, ""},
+		{"cancel shipped", OrderStatusShipped, false, OrderActionCancel, true, ""},
+		{"cancel in cancel", OrderStatusInCancel, false, OrderActionCancel, true, ""},
+		{"accept without request", OrderStatusReadyToShip, false, OrderActionAcceptBuyerCancellation, true, ""},
+		{"reject request", OrderStatusInCancel, false, OrderActionRejectBuyerCancellation, false, ""},
+		{"split", OrderStatusReadyToShip, false, OrderActionSplit, false, ""},
+		{"split twice", OrderStatusReadyToShip, true, OrderActionSplit, true, "already split"},
+		{"undo split", OrderStatusReadyToShip, true, OrderActionUndoSplit, false, ""},
+		{"undo split not split", OrderStatusReadyToShip, false, OrderActionUndoSplit, true, "not split"},
+		{"split unpaid", OrderStatusUnpaid, false, OrderActionSplit, true, ""},
+		{"ship completed", OrderStatusCompleted, false, OrderActionInitLogistics, true, ""},
+		{"note on completed", OrderStatusCompleted, false, OrderActionAddNote, false, ""},
+		{"unknown status", OrderStatus("LOST"), false, OrderActionAddNote, true, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			order := &Order{OrderSN: "SN1", Status: tc.status, IsSplitUp: FlexBool(tc.split)}
+			err := order.CheckAction(tc.action)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CheckAction = %v, want error %t", err, tc.wantErr)
+			}
+			if got := order.Can(tc.action); got != !tc.wantErr {
+				t.Errorf("Can = %t, want %t", got, !tc.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			e, ok := err.(InvalidOrderActionError)
+			if !ok {
+				t.Fatalf("CheckAction error = %#v, want an InvalidOrderActionError", err)
+			}
+			want := InvalidOrderActionError{OrderSN: "SN1", Status: tc.status, Action: tc.action, Reason: tc.wantReason}
+			if e != want {
+				t.Errorf("CheckAction error = %+v, want %+v", e, want)
+			}
+		})
+	}
+}
+
+func TestOrderNextActions(t *testing.T) {
+	cases := []struct {
+		status OrderStatus
+		split  bool
+		want   []OrderAction
+	}{
+		{OrderStatusUnpaid, false, []OrderAction{OrderActionAddNote, OrderActionCancel}},
+		{OrderStatusReadyToShip, false, []OrderAction{OrderActionAddNote, OrderActionCancel, OrderActionInitLogistics, OrderActionSplit}},
+		{OrderStatusReadyToShip, true, []OrderAction{OrderActionAddNote, OrderActionCancel, OrderActionInitLogistics, OrderActionUndoSplit}},
+		{OrderStatusRetryShip, false, []OrderAction{OrderActionAddNote, OrderActionInitLogistics}},
+		{OrderStatusInCancel, false, []OrderAction{OrderActionAcceptBuyerCancellation, OrderActionAddNote, OrderActionRejectBuyerCancellation}},
+		{OrderStatusShipped, false, []OrderAction{OrderActionAddNote}},
+		{OrderStatusCompleted, false, []OrderAction{OrderActionAddNote}},
+		{OrderStatus("LOST"), false, nil},
+	}
+	for _, tc := range cases {
+		order := &Order{OrderSN: "SN1", Status: tc.status, IsSplitUp: FlexBool(tc.split)}
+		if got := order.NextActions(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("NextActions of %s (split %t) = %v, want %v", tc.status, tc.split, got, tc.want)
+		}
+	}
+}
+
+func TestInvalidOrderActionError(t *testing.T) {
+	err := InvalidOrderActionError{OrderSN: "SN1", Status: OrderStatusReadyToShip, Action: OrderActionSplit, Reason: "already split"}
+	if want := "order SN1: split is not allowed in status READY_TO_SHIP: already split"; err.Error() != want {
+		t.Errorf("Error = %q, want %q", err.Error(), want)
+	}
+}
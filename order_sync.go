@@ -0,0 +1,316 @@
+package goshopee
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSyncOverlap   = 10 * time.Minute
+	defaultSyncLookback  = 15 * 24 * time.Hour
+	defaultSyncBatchSize = 50
+)
+
+// OrderEventType tells what happened to an order since the last sync.
+type OrderEventType string
+
+const (
+	OrderEventCreated       OrderEventType = "created"
+	OrderEventUpdated       OrderEventType = "updated"
+	OrderEventStatusChanged OrderEventType = "status_changed"
+)
+
+// OrderEvent is emitted by an OrderSyncer for every new or changed order.
+type OrderEvent struct {
+	Type   OrderEventType
+	ShopID uint64
+	Order  Order
+
+	// PreviousStatus is the status the order had at the previous sync, empty
+	// when the order was not known.
+	PreviousStatus OrderStatus
+}
+
+// OrderVersion is the state of an order as last seen by an OrderSyncer.
+type OrderVersion struct {
+	Status     OrderStatus `json:"status"`
+	UpdateTime int64       `json:"update_time"`
+}
+
+// OrderCheckpoint is the progress of an OrderSyncer for a shop.
+type OrderCheckpoint struct {
+	// UpdateTime is the update time, in unix seconds, up to which the orders
+	// were synced.
+	UpdateTime int64 `json:"update_time"`
+
+	// Orders are the orders still open or updated within the overlap, used
+	// to detect status changes and to skip the orders already emitted.
+	Orders map[string]OrderVersion `json:"orders"`
+
+	// Pending are the orders listed but missing from the order details,
+	// fetched again by the next sync.
+	Pending []string `json:"pending,omitempty"`
+}
+
+// CheckpointStore persists OrderSyncer checkpoints.
+type CheckpointStore interface {
+	// Load returns the checkpoint of the shop, nil when there is none yet.
+	Load(ctx context.Context, sid uint64) (*OrderCheckpoint, error)
+	Save(ctx context.Context, sid uint64, checkpoint *OrderCheckpoint) error
+}
+
+// MemoryCheckpointStore keeps checkpoints in memory.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[uint64][]byte
+}
+
+func (s *MemoryCheckpointStore) Load(ctx context.Context, sid uint64) (*OrderCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.checkpoints[sid]
+	if !ok {
+		return nil, nil
+	}
+	checkpoint := new(OrderCheckpoint)
+	if err := json.Unmarshal(content, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+func (s *MemoryCheckpointStore) Save(ctx context.Context, sid uint64, checkpoint *OrderCheckpoint) error {
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checkpoints == nil {
+		s.checkpoints = map[uint64][]byte{}
+	}
+	s.checkpoints[sid] = content
+	return nil
+}
+
+// FileCheckpointStore keeps checkpoints as JSON files in a directory, one
+// file per shop.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+func (s *FileCheckpointStore) path(sid uint64) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("orders-%d.json", sid))
+}
+
+func (s *FileCheckpointStore) Load(ctx context.Context, sid uint64) (*OrderCheckpoint, error) {
+	content, err := ioutil.ReadFile(s.path(sid))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := new(OrderCheckpoint)
+	if err := json.Unmarshal(content, checkpoint); err != nil {
+		return nil, fmt.Errorf("decode checkpoint %s: %s", s.path(sid), err)
+	}
+	return checkpoint, nil
+}
+
+func (s *FileCheckpointStore) Save(ctx context.Context, sid uint64, checkpoint *OrderCheckpoint) error {
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	// write then rename so a crash never leaves a truncated checkpoint
+	tmp := s.path(sid) + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(sid))
+}
+
+// OrderSyncer incrementally syncs the orders of a shop. Every Sync lists the
+// orders updated since the checkpoint, fetches their details in batches and
+// passes an OrderEvent per new or changed order to the handler, then saves
+// the checkpoint. The listing starts Overlap before the checkpoint so orders
+// updated late or stamped by a skewed clock are not missed, the orders seen
+// twice are skipped. The orders listed but missing from the details are kept
+// in the checkpoint and fetched again by the next sync.
+type OrderSyncer struct {
+	client  *Client
+	shopID  uint64
+	store   CheckpointStore
+	handler func(context.Context, OrderEvent) error
+
+	// Overlap is how far before the checkpoint a sync starts, defaults to
+	// 10 minutes.
+	Overlap time.Duration
+
+	// Lookback is how far back the first sync of a shop goes, defaults to
+	// 15 days.
+	Lookback time.Duration
+
+	// BatchSize is the number of orders fetched per GetMulti call, defaults
+	// to 50.
+	BatchSize int
+}
+
+// NewOrderSyncer returns an OrderSyncer for shop sid, saving its progress to
+// store and passing the events to handler. When the handler fails the sync
+// stops without saving the checkpoint, so the events are delivered again by
+// the next sync.
+func NewOrderSyncer(client *Client, sid uint64, store CheckpointStore, handler func(context.Context, OrderEvent) error) *OrderSyncer {
+	return &OrderSyncer{
+		client:    client,
+		shopID:    sid,
+		store:     store,
+		handler:   handler,
+		Overlap:   defaultSyncOverlap,
+		Lookback:  defaultSyncLookback,
+		BatchSize: defaultSyncBatchSize,
+	}
+}
+
+// Run syncs every interval until ctx is done. Failed syncs are logged and
+// retried at the next interval.
+func (s *OrderSyncer) Run(ctx context.Context, interval time.Duration) error {
+	for {
+		if err := s.Sync(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.client.log.Errorf("sync orders of shop %d: %s", s.shopID, err)
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// Sync runs a single sync pass.
+func (s *OrderSyncer) Sync(ctx context.Context) error {
+	checkpoint, err := s.store.Load(ctx, s.shopID)
+	if err != nil {
+		return fmt.Errorf("load checkpoint: %s", err)
+	}
+	now := s.client.now()
+	if checkpoint == nil {
+		checkpoint = &OrderCheckpoint{UpdateTime: now.Add(-s.Lookback).Unix()}
+	}
+	if checkpoint.Orders == nil {
+		checkpoint.Orders = map[string]OrderVersion{}
+	}
+
+	from := time.Unix(checkpoint.UpdateTime, 0).Add(-s.Overlap)
+	if from.After(now) {
+		// the checkpoint is ahead of the local clock
+		from = now
+	}
+	query := OrderQuery{TimeField: OrderUpdateTime, From: from, To: now}
+
+	// the orders missing at the previous sync may not be listed again
+	changed := append([]string(nil), checkpoint.Pending...)
+	pending := make(map[string]bool, len(changed))
+	for _, sn := range changed {
+		pending[sn] = true
+	}
+	for order, err := range s.client.Order.Query(ctx, s.shopID, query) {
+		if err != nil {
+			return err
+		}
+		prev, ok := checkpoint.Orders[order.OrderSN]
+		if pending[order.OrderSN] || ok && prev.UpdateTime == order.UpdateTime && prev.Status == order.Status {
+			continue
+		}
+		changed = append(changed, order.OrderSN)
+	}
+
+	orders, missing, err := s.details(ctx, changed)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		s.client.log.Warnf("orders %v of shop %d have no details yet, retrying at the next sync", missing, s.shopID)
+	}
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].UpdateTime < orders[j].UpdateTime })
+
+	for _, order := range orders {
+		event := OrderEvent{ShopID: s.shopID, Order: order}
+		prev, ok := checkpoint.Orders[order.OrderSN]
+		switch {
+		case ok && prev.Status != order.Status:
+			event.Type = OrderEventStatusChanged
+			event.PreviousStatus = prev.Status
+		case ok:
+			event.Type = OrderEventUpdated
+			event.PreviousStatus = prev.Status
+		case order.CreateTime >= from.Unix():
+			event.Type = OrderEventCreated
+		default:
+			event.Type = OrderEventUpdated
+		}
+		if err := s.handler(ctx, event); err != nil {
+			return err
+		}
+		checkpoint.Orders[order.OrderSN] = OrderVersion{Status: order.Status, UpdateTime: order.UpdateTime}
+	}
+
+	checkpoint.UpdateTime = now.Unix()
+	checkpoint.Pending = missing
+	s.prune(checkpoint)
+	if err := s.store.Save(ctx, s.shopID, checkpoint); err != nil {
+		return fmt.Errorf("save checkpoint: %s", err)
+	}
+	return nil
+}
+
+// details fetches the orders in batches, returning the ordersn which were
+// not found along with them.
+func (s *OrderSyncer) details(ctx context.Context, ordersn []string) ([]Order, []string, error) {
+	batchSize := s.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSyncBatchSize
+	}
+	var (
+		orders  []Order
+		missing []string
+	)
+	for start := 0; start < len(ordersn); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		end := start + batchSize
+		if end > len(ordersn) {
+			end = len(ordersn)
+		}
+		batch, notFound, err := s.client.Order.GetMulti(ctx, s.shopID, ordersn[start:end])
+		if err != nil {
+			return nil, nil, err
+		}
+		orders = append(orders, batch...)
+		missing = append(missing, notFound...)
+	}
+	return orders, missing, nil
+}
+
+// prune forgets the closed orders which can not show up in the next sync.
+func (s *OrderSyncer) prune(checkpoint *OrderCheckpoint) {
+	horizon := time.Unix(checkpoint.UpdateTime, 0).Add(-s.Overlap).Unix()
+	for sn, v := range checkpoint.Orders {
+		if v.UpdateTime < horizon && v.Status.IsFinal() {
+			delete(checkpoint.Orders, sn)
+		}
+	}
+}
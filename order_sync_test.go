@@ -0,0 +1,216 @@
+package goshopee_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+	"github.com/passwind/go-shopee/mocks"
+)
+
+// syncClock is a settable clock shared by the client and the fake server.
+type syncClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *syncClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *syncClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// syncEvent is the part of an OrderEvent checked by the tests.
+type syncEvent struct {
+	Type           goshopee.OrderEventType
+	OrderSN        string
+	PreviousStatus goshopee.OrderStatus
+}
+
+// newSyncTest returns a fake server and a client sharing clock.
+func newSyncTest(clock *syncClock) (*goshopeetest.Server, *goshopee.Client) {
+	srv := goshopeetest.NewServer(1, "key")
+	srv.SetClock(clock)
+	return srv, srv.Client(goshopee.WithClock(clock))
+}
+
+// syncEvents runs a sync and returns its events sorted by ordersn.
+func syncEvents(t *testing.T, client *goshopee.Client, store goshopee.CheckpointStore) []syncEvent {
+	t.Helper()
+	var events []syncEvent
+	syncer := goshopee.NewOrderSyncer(client, 7, store, func(ctx context.Context, e goshopee.OrderEvent) error {
+		events = append(events, syncEvent{Type: e.Type, OrderSN: e.Order.OrderSN, PreviousStatus: e.PreviousStatus})
+		return nil
+	})
+	if err := syncer.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].OrderSN < events[j].OrderSN })
+	return events
+}
+
+func TestOrderSyncerEvents(t *testing.T) {
+	clock := &syncClock{now: time.Now().Truncate(time.Second)}
+	srv, client := newSyncTest(clock)
+	defer srv.Close()
+	store := &goshopee.MemoryCheckpointStore{}
+
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN1", CreateTime: clock.Now().Add(-time.Hour).Unix()})
+	want := []syncEvent{{Type: goshopee.OrderEventCreated, OrderSN: "SN1"}}
+	if got := syncEvents(t, client, store); !reflect.DeepEqual(got, want) {
+		t.Fatalf("first sync = %+v, want %+v", got, want)
+	}
+
+	clock.Add(time.Hour)
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN2"})
+	srv.SetOrderStatus(7, "SN1", goshopee.OrderStatusShipped)
+	want = []syncEvent{
+		{Type: goshopee.OrderEventStatusChanged, OrderSN: "SN1", PreviousStatus: goshopee.OrderStatusReadyToShip},
+		{Type: goshopee.OrderEventCreated, OrderSN: "SN2"},
+	}
+	if got := syncEvents(t, client, store); !reflect.DeepEqual(got, want) {
+		t.Fatalf("second sync = %+v, want %+v", got, want)
+	}
+
+	// both orders are listed again within the overlap, but did not change
+	clock.Add(5 * time.Minute)
+	if got := syncEvents(t, client, store); len(got) != 0 {
+		t.Fatalf("sync within the overlap = %+v, want no event", got)
+	}
+
+	clock.Add(time.Hour)
+	sn2, _ := srv.Order(7, "SN2")
+	sn2.UpdateTime = clock.Now().Unix()
+	srv.AddOrder(7, sn2)
+	want = []syncEvent{{Type: goshopee.OrderEventUpdated, OrderSN: "SN2", PreviousStatus: goshopee.OrderStatusReadyToShip}}
+	if got := syncEvents(t, client, store); !reflect.DeepEqual(got, want) {
+		t.Fatalf("sync after an update = %+v, want %+v", got, want)
+	}
+}
+
+func TestOrderSyncerHandlerError(t *testing.T) {
+	clock := &syncClock{now: time.Now().Truncate(time.Second)}
+	srv, client := newSyncTest(clock)
+	defer srv.Close()
+	store := &goshopee.MemoryCheckpointStore{}
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN1"})
+
+	failure := errors.New("handler failed")
+	syncer := goshopee.NewOrderSyncer(client, 7, store, func(ctx context.Context, e goshopee.OrderEvent) error {
+		return failure
+	})
+	if err := syncer.Sync(context.Background()); err != failure {
+		t.Fatalf("Sync = %v, want the handler error", err)
+	}
+	if checkpoint, err := store.Load(context.Background(), 7); err != nil || checkpoint != nil {
+		t.Fatalf("checkpoint = %+v, %v, want none saved", checkpoint, err)
+	}
+
+	want := []syncEvent{{Type: goshopee.OrderEventCreated, OrderSN: "SN1"}}
+	if got := syncEvents(t, client, store); !reflect.DeepEqual(got, want) {
+		t.Errorf("sync after the failure = %+v, want %+v", got, want)
+	}
+}
+
+func TestOrderSyncerMissingDetails(t *testing.T) {
+	clock := &syncClock{now: time.Now().Truncate(time.Second)}
+	srv, client := newSyncTest(clock)
+	defer srv.Close()
+	store := &goshopee.MemoryCheckpointStore{}
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN1"})
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN2"})
+
+	// the details of SN2 are missing at the first call
+	orders := client.Order
+	missed := false
+	client.Order = &mocks.OrderService{
+		QueryFunc: orders.Query,
+		GetMultiFunc: func(ctx context.Context, sid uint64, ordersn []string) ([]goshopee.Order, []string, error) {
+			found, notFound, err := orders.GetMulti(ctx, sid, ordersn)
+			if missed {
+				return found, notFound, err
+			}
+			missed = true
+			var kept []goshopee.Order
+			for _, o := range found {
+				if o.OrderSN == "SN2" {
+					notFound = append(notFound, o.OrderSN)
+					continue
+				}
+				kept = append(kept, o)
+			}
+			return kept, notFound, err
+		},
+	}
+
+	want := []syncEvent{{Type: goshopee.OrderEventCreated, OrderSN: "SN1"}}
+	if got := syncEvents(t, client, store); !reflect.DeepEqual(got, want) {
+		t.Fatalf("first sync = %+v, want %+v", got, want)
+	}
+	checkpoint, err := store.Load(context.Background(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"SN2"}; !reflect.DeepEqual(checkpoint.Pending, want) {
+		t.Fatalf("Pending = %v, want %v", checkpoint.Pending, want)
+	}
+
+	// SN2 was never emitted, it still is a new order
+	clock.Add(24 * time.Hour)
+	want = []syncEvent{{Type: goshopee.OrderEventCreated, OrderSN: "SN2"}}
+	if got := syncEvents(t, client, store); !reflect.DeepEqual(got, want) {
+		t.Fatalf("second sync = %+v, want %+v", got, want)
+	}
+	if checkpoint, _ = store.Load(context.Background(), 7); len(checkpoint.Pending) != 0 {
+		t.Errorf("Pending = %v, want none", checkpoint.Pending)
+	}
+}
+
+func TestOrderSyncerPrune(t *testing.T) {
+	clock := &syncClock{now: time.Now().Truncate(time.Second)}
+	srv, client := newSyncTest(clock)
+	defer srv.Close()
+	store := &goshopee.MemoryCheckpointStore{}
+
+	now := clock.Now().Unix()
+	old := now - 24*3600
+	recent := now - 60
+	err := store.Save(context.Background(), 7, &goshopee.OrderCheckpoint{
+		UpdateTime: now,
+		Orders: map[string]goshopee.OrderVersion{
+			"OLD_COMPLETED":    {Status: goshopee.OrderStatusCompleted, UpdateTime: old},
+			"OLD_CANCELLED":    {Status: goshopee.OrderStatusCancelled, UpdateTime: old},
+			"OLD_SHIPPED":      {Status: goshopee.OrderStatusShipped, UpdateTime: old},
+			"RECENT_COMPLETED": {Status: goshopee.OrderStatusCompleted, UpdateTime: recent},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	syncEvents(t, client, store)
+	checkpoint, err := store.Load(context.Background(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []string
+	for sn := range checkpoint.Orders {
+		kept = append(kept, sn)
+	}
+	sort.Strings(kept)
+	if want := []string{"OLD_SHIPPED", "RECENT_COMPLETED"}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept orders = %v, want %v", kept, want)
+	}
+}
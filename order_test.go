@@ -0,0 +1,264 @@
+package goshopee_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+)
+
+func TestOrderIterTimeRange(t *testing.T) {
+	now := time.Now().Unix()
+	cases := []struct {
+		name    string
+		options map[string]interface{}
+		want    []string
+		// wantKeys are the time options sent with each page request
+		wantKeys []string
+	}{
+		{
+			name:     "default window",
+			want:     []string{"SN1", "SN2"},
+			wantKeys: []string{"create_time_from", "create_time_to"},
+		},
+		{
+			name:     "update range",
+			options:  map[string]interface{}{"update_time_from": now - 3600, "update_time_to": now},
+			want:     []string{"SN2"},
+			wantKeys: []string{"update_time_from", "update_time_to"},
+		},
+		{
+			name:     "create range",
+			options:  map[string]interface{}{"create_time_from": now - 3*24*3600, "create_time_to": now - 24*3600},
+			want:     []string{"SN1"},
+			wantKeys: []string{"create_time_from", "create_time_to"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			srv.AddOrder(7, goshopee.Order{OrderSN: "SN1", CreateTime: now - 2*24*3600, UpdateTime: now - 2*24*3600})
+			srv.AddOrder(7, goshopee.Order{OrderSN: "SN2", CreateTime: now - 600, UpdateTime: now - 600})
+
+			var got []string
+			for order, err := range srv.Client().Order.Iter(context.Background(), 7, tc.options, goshopee.Cursor{}).All() {
+				if err != nil {
+					t.Fatalf("Iter: %v", err)
+				}
+				got = append(got, order.OrderSN)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("orders = %v, want %v", got, tc.want)
+			}
+
+			for _, r := range srv.Requests() {
+				var body map[string]interface{}
+				if err := json.Unmarshal(r.Body, &body); err != nil {
+					t.Fatal(err)
+				}
+				var keys []string
+				for _, k := range []string{"create_time_from", "create_time_to", "update_time_from", "update_time_to"} {
+					if _, ok := body[k]; ok {
+						keys = append(keys, k)
+					}
+				}
+				if !reflect.DeepEqual(keys, tc.wantKeys) {
+					t.Errorf("%s sent %v, want %v", r.Path, keys, tc.wantKeys)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderIterResume(t *testing.T) {
+	const day = 24 * 3600
+	start := time.Now().Add(-30 * 24 * time.Hour).Truncate(time.Second)
+	t0 := start.Unix()
+	srv := goshopeetest.NewServer(1, "key")
+	defer srv.Close()
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN1", CreateTime: t0 - 15*day + 3600})
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN2", CreateTime: t0 - 10*day})
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN3", CreateTime: t0 - 5*day})
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN4", CreateTime: t0 + day/2})
+
+	client := srv.Client(goshopee.WithClock(goshopee.ClockFunc(func() time.Time { return start })))
+	pager := client.Order.Iter(context.Background(), 7, nil, goshopee.Cursor{})
+	for order, err := range pager.All() {
+		if err != nil {
+			t.Fatalf("Iter: %v", err)
+		}
+		if order.OrderSN != "SN1" {
+			t.Fatalf("first order = %s, want SN1", order.OrderSN)
+		}
+		break
+	}
+	saved, err := json.Marshal(pager.Cursor())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a day later, another process resumes from the saved cursor
+	var cursor goshopee.Cursor
+	if err := json.Unmarshal(saved, &cursor); err != nil {
+		t.Fatal(err)
+	}
+	later := start.Add(24 * time.Hour)
+	client = srv.Client(goshopee.WithClock(goshopee.ClockFunc(func() time.Time { return later })))
+	var got []string
+	for order, err := range client.Order.Iter(context.Background(), 7, nil, cursor).All() {
+		if err != nil {
+			t.Fatalf("Iter: %v", err)
+		}
+		got = append(got, order.OrderSN)
+	}
+	if want := []string{"SN2", "SN3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("resumed orders = %v, want %v", got, want)
+	}
+}
+
+func TestOrderQueryStatus(t *testing.T) {
+	srv := goshopeetest.NewServer(1, "key")
+	defer srv.Close()
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN1"})
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN2", Status: goshopee.OrderStatusCompleted})
+	client := srv.Client()
+
+	var got []string
+	query := goshopee.OrderQuery{Status: goshopee.OrderStatusCompleted}
+	for order, err := range client.Order.Query(context.Background(), 7, query) {
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		got = append(got, order.OrderSN)
+	}
+	if want := []string{"SN2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("orders = %v, want %v", got, want)
+	}
+
+	query.TimeField = goshopee.OrderUpdateTime
+	for _, err := range client.Order.Query(context.Background(), 7, query) {
+		if err == nil {
+			t.Fatal("Query by status and update time yielded an order, want an error")
+		}
+	}
+	now := time.Now().Unix()
+	options := map[string]interface{}{"order_status": "COMPLETED", "update_time_from": now - 3600, "update_time_to": now}
+	if _, _, err := client.Order.ListWithPagination(7, 0, 10, options); err == nil {
+		t.Error("ListWithPagination by status and update time succeeded, want an error")
+	}
+	for _, r := range srv.Requests() {
+		var body map[string]interface{}
+		if err := json.Unmarshal(r.Body, &body); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := body["update_time_from"]; ok {
+			t.Errorf("%s sent update_time_from, want only create time ranges", r.Path)
+		}
+	}
+}
+
+func TestOrderGetMulti(t *testing.T) {
+	var many []string
+	for i := 0; i < 120; i++ {
+		many = append(many, fmt.Sprintf("SN%03d", i))
+	}
+	cases := []struct {
+		name        string
+		ordersn     []string
+		want        []string
+		wantMissing []string
+		wantCalls   int
+	}{
+		{name: "none"},
+		{name: "one", ordersn: []string{"SN001"}, want: []string{"SN001"}, wantCalls: 1},
+		{name: "chunked", ordersn: many, want: many, wantCalls: 3},
+		{name: "order kept", ordersn: []string{"SN099", "SN000", "SN050"}, want: []string{"SN099", "SN000", "SN050"}, wantCalls: 1},
+		{name: "duplicates", ordersn: []string{"SN001", "SN002", "SN001"}, want: []string{"SN001", "SN002"}, wantCalls: 1},
+		{
+			name:        "not found",
+			ordersn:     []string{"SN001", "X1", "SN002", "X1", "X2"},
+			want:        []string{"SN001", "SN002"},
+			wantMissing: []string{"X1", "X2"},
+			wantCalls:   1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			for _, sn := range many {
+				srv.AddOrder(7, goshopee.Order{OrderSN: sn})
+			}
+
+			orders, missing, err := srv.Client().Order.GetMulti(context.Background(), 7, tc.ordersn)
+			if err != nil {
+				t.Fatalf("GetMulti: %v", err)
+			}
+			var got []string
+			for _, o := range orders {
+				got = append(got, o.OrderSN)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("orders = %v, want %v", got, tc.want)
+			}
+			if !reflect.DeepEqual(missing, tc.wantMissing) {
+				t.Errorf("missing = %v, want %v", missing, tc.wantMissing)
+			}
+			if calls := len(srv.Requests()); calls != tc.wantCalls {
+				t.Errorf("%d calls, want %d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestOrderGetMultiError(t *testing.T) {
+	srv := goshopeetest.NewServer(1, "key")
+	defer srv.Close()
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN1"})
+	srv.InjectError("/orders/detail", 1, "error_server", "try later")
+
+	orders, missing, err := srv.Client().Order.GetMulti(context.Background(), 7, []string{"SN1"})
+	if err == nil || orders != nil || missing != nil {
+		t.Errorf("GetMulti = %v, %v, %v, want an error only", orders, missing, err)
+	}
+}
+
+func TestOrderGetMultiStopsAtFirstError(t *testing.T) {
+	srv := goshopeetest.NewServer(1, "key")
+	defer srv.Close()
+	var ordersn []string
+	for i := 0; i < 600; i++ {
+		ordersn = append(ordersn, fmt.Sprintf("SN%03d", i))
+	}
+	srv.InjectError("/orders/detail", 0, "error_server", "down")
+
+	if _, _, err := srv.Client().Order.GetMulti(context.Background(), 7, ordersn); err == nil {
+		t.Fatal("GetMulti succeeded, want an error")
+	}
+	// only the chunks already running when the first one failed were sent
+	if n := len(srv.Requests()); n == 0 || n > 4 {
+		t.Errorf("sent %d of 12 chunks, want at most 4", n)
+	}
+}
+
+func TestOrderGetMultiCancelled(t *testing.T) {
+	srv := goshopeetest.NewServer(1, "key")
+	defer srv.Close()
+	srv.AddOrder(7, goshopee.Order{OrderSN: "SN1"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	orders, missing, err := srv.Client().Order.GetMulti(ctx, 7, []string{"SN1"})
+	if err != context.Canceled || orders != nil || missing != nil {
+		t.Errorf("GetMulti = %v, %v, %v, want %v only", orders, missing, err, context.Canceled)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("sent %d requests, want none", n)
+	}
+}
@@ -0,0 +1,129 @@
+package goshopee
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// Cursor is the position of a Pager in a paginated listing. It can be saved
+// and given back to the listing's Iter method to resume an iteration.
+type Cursor struct {
+	// Offset of the next entry to yield.
+	Offset uint32 `json:"offset"`
+
+	// WindowFrom and WindowTo are the time window, in unix seconds, of the
+	// listings defaulting to one computed when the iteration starts, such as
+	// the orders listed without a time range, so a resumed iteration walks
+	// the same window as the first one. Zero for the other listings.
+	WindowFrom int64 `json:"window_from,omitempty"`
+	WindowTo   int64 `json:"window_to,omitempty"`
+}
+
+// PageFunc fetches the page of a listing starting at offset, returning the
+// entries of the page and whether more entries follow it.
+type PageFunc[T any] func(ctx context.Context, offset uint32) ([]T, bool, error)
+
+// Pager lazily iterates over a paginated listing. The next page is fetched
+// in the background while the entries of the current one are consumed.
+type Pager[T any] struct {
+	ctx   context.Context
+	fetch PageFunc[T]
+
+	mu     sync.Mutex
+	cursor Cursor
+	done   bool
+}
+
+// NewPager returns a Pager over the pages returned by fetch, starting at from.
+func NewPager[T any](ctx context.Context, fetch PageFunc[T], from Cursor) *Pager[T] {
+	return &Pager[T]{ctx: ctx, fetch: fetch, cursor: from}
+}
+
+// Cursor returns the position after the last entry yielded.
+func (p *Pager[T]) Cursor() Cursor {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cursor
+}
+
+// Done reports whether every entry of the listing was yielded.
+func (p *Pager[T]) Done() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.done
+}
+
+func (p *Pager[T]) advance(offset uint32, done bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cursor.Offset = offset
+	p.done = done
+}
+
+type page[T any] struct {
+	offset  uint32
+	entries []T
+	more    bool
+	err     error
+}
+
+// All returns an iterator over the entries of the listing, starting at the
+// current cursor. Iteration stops at the first error, which is yielded with
+// a zero entry, or when the context is cancelled.
+func (p *Pager[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		ctx, cancel := context.WithCancel(p.ctx)
+		defer cancel()
+
+		fetch := func(offset uint32) <-chan page[T] {
+			ch := make(chan page[T], 1)
+			go func() {
+				entries, more, err := p.fetch(ctx, offset)
+				ch <- page[T]{offset: offset, entries: entries, more: more, err: err}
+			}()
+			return ch
+		}
+
+		if p.Done() {
+			return
+		}
+		next := fetch(p.Cursor().Offset)
+		for next != nil {
+			var current page[T]
+			select {
+			case <-ctx.Done():
+				yield(zero, ctx.Err())
+				return
+			case current = <-next:
+			}
+			if current.err != nil {
+				yield(zero, current.err)
+				return
+			}
+
+			// prefetch the next page while this one is consumed
+			next = nil
+			more := current.more && len(current.entries) > 0
+			if more {
+				next = fetch(current.offset + uint32(len(current.entries)))
+			}
+			if len(current.entries) == 0 {
+				p.advance(current.offset, true)
+			}
+
+			for i, entry := range current.entries {
+				if err := ctx.Err(); err != nil {
+					yield(zero, err)
+					return
+				}
+				last := i == len(current.entries)-1
+				p.advance(current.offset+uint32(i)+1, last && !more)
+				if !yield(entry, nil) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package goshopee
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// pages serves the entries 0..n-1 in pages of size.
+func pages(n, size int) PageFunc[int] {
+	return func(ctx context.Context, offset uint32) ([]int, bool, error) {
+		var entries []int
+		for i := int(offset); i < n && i < int(offset)+size; i++ {
+			entries = append(entries, i)
+		}
+		return entries, int(offset)+len(entries) < n, nil
+	}
+}
+
+func collect(t *testing.T, p *Pager[int], limit int) []int {
+	t.Helper()
+	var got []int
+	for v, err := range p.All() {
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		got = append(got, v)
+		if len(got) == limit {
+			break
+		}
+	}
+	return got
+}
+
+func TestPager(t *testing.T) {
+	cases := []struct {
+		name       string
+		n, size    int
+		from       Cursor
+		want       []int
+		wantCursor Cursor
+	}{
+		{"empty", 0, 3, Cursor{}, nil, Cursor{}},
+		{"one page", 2, 3, Cursor{}, []int{0, 1}, Cursor{Offset: 2}},
+		{"full pages", 6, 3, Cursor{}, []int{0, 1, 2, 3, 4, 5}, Cursor{Offset: 6}},
+		{"partial last page", 7, 3, Cursor{}, []int{0, 1, 2, 3, 4, 5, 6}, Cursor{Offset: 7}},
+		{"from cursor", 7, 3, Cursor{Offset: 4}, []int{4, 5, 6}, Cursor{Offset: 7}},
+		{"from end", 7, 3, Cursor{Offset: 7}, nil, Cursor{Offset: 7}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPager(context.Background(), pages(tc.n, tc.size), tc.from)
+			got := collect(t, p, -1)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("entries = %v, want %v", got, tc.want)
+			}
+			if p.Cursor() != tc.wantCursor || !p.Done() {
+				t.Errorf("Cursor = %+v, Done = %t, want %+v, true", p.Cursor(), p.Done(), tc.wantCursor)
+			}
+		})
+	}
+}
+
+func TestPagerResume(t *testing.T) {
+	for _, stop := range []int{1, 3, 4, 6} {
+		fetch := pages(7, 3)
+		p := NewPager(context.Background(), fetch, Cursor{})
+		got := collect(t, p, stop)
+		if p.Done() {
+			t.Fatalf("stopped after %d: Done before the end", stop)
+		}
+
+		resumed := NewPager(context.Background(), fetch, p.Cursor())
+		got = append(got, collect(t, resumed, -1)...)
+		if want := []int{0, 1, 2, 3, 4, 5, 6}; !reflect.DeepEqual(got, want) {
+			t.Errorf("stopped after %d: entries = %v, want %v", stop, got, want)
+		}
+	}
+}
+
+func TestPagerCancelPrefetch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	prefetched := make(chan struct{})
+	aborted := make(chan error, 1)
+	fetch := func(ctx context.Context, offset uint32) ([]int, bool, error) {
+		if offset == 0 {
+			return []int{0, 1}, true, nil
+		}
+		// the second page only returns once the iteration is cancelled
+		close(prefetched)
+		<-ctx.Done()
+		aborted <- ctx.Err()
+		return nil, false, ctx.Err()
+	}
+
+	p := NewPager(ctx, fetch, Cursor{})
+	var got []int
+	var gotErr error
+	for v, err := range p.All() {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		got = append(got, v)
+		if v == 0 {
+			<-prefetched
+			cancel()
+		}
+	}
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", gotErr)
+	}
+	if !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("entries = %v, want [0]", got)
+	}
+	if err := <-aborted; !errors.Is(err, context.Canceled) {
+		t.Errorf("prefetch ctx error = %v, want context.Canceled", err)
+	}
+	if p.Done() || p.Cursor().Offset != 1 {
+		t.Errorf("Cursor = %+v, Done = %t, want offset 1 not done", p.Cursor(), p.Done())
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	boom := errors.New("boom")
+	fetch := func(ctx context.Context, offset uint32) ([]int, bool, error) {
+		if offset == 0 {
+			return []int{0, 1}, true, nil
+		}
+		return nil, false, boom
+	}
+	p := NewPager(context.Background(), fetch, Cursor{})
+	var got []int
+	var gotErr error
+	for v, err := range p.All() {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		got = append(got, v)
+	}
+	if gotErr != boom || !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("entries = %v, error = %v, want [0 1], boom", got, gotErr)
+	}
+	if p.Done() || p.Cursor().Offset != 2 {
+		t.Errorf("Cursor = %+v, Done = %t, want offset 2 not done", p.Cursor(), p.Done())
+	}
+}
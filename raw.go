@@ -0,0 +1,218 @@
+package goshopee
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RawFields keeps what Shopee actually sent for a model, so fields added to
+// the API after this package was released are not lost. Models carry it as
+// a Raw field which is only filled when the client is created with
+// WithRawFields.
+type RawFields struct {
+	// JSON is the object exactly as received.
+	JSON json.RawMessage
+	// Unknown holds the fields of the object the model does not declare,
+	// keyed by JSON name.
+	Unknown map[string]json.RawMessage
+}
+
+// SchemaDrift reports the fields of a response the models do not declare,
+// see WithStrictDecoding.
+type SchemaDrift struct {
+	// Path is the API path of the request, e.g. /api/v1/orders/detail.
+	Path string
+	// Fields are the unknown fields as dotted JSON paths, with [] standing
+	// for any array index, e.g. orders[].new_field.
+	Fields []string
+}
+
+// SchemaDriftFunc is called with every response that has unknown fields
+// when the client is created with WithStrictDecoding, typically to count
+// them in a metrics system.
+type SchemaDriftFunc func(SchemaDrift)
+
+// checkUnknownFields fills the Raw fields of v, decoded from the response
+// to path, and reports its unknown fields as configured on the client.
+func (c *Client) checkUnknownFields(path string, content []byte, v interface{}) {
+	fields := decodeUnknown(content, v, c.rawFields)
+	if !c.strict || len(fields) == 0 {
+		return
+	}
+	c.log.Warnf("schema drift in response to %s: unknown fields %s", path, strings.Join(fields, ", "))
+	if c.onDrift != nil {
+		c.onDrift(SchemaDrift{Path: path, Fields: fields})
+	}
+}
+
+var (
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	rawFieldsType   = reflect.TypeOf((*RawFields)(nil))
+)
+
+// rawWalker walks a decoded value alongside the JSON it was decoded from,
+// collecting the object keys no struct field claimed.
+type rawWalker struct {
+	keep    bool                // fill the Raw fields of the models
+	unknown map[string]struct{} // paths of the unknown fields
+}
+
+// decodeUnknown walks v, already decoded from data, and returns the sorted
+// paths of the unknown fields. keep fills in the Raw field of every model
+// met on the way.
+func decodeUnknown(data []byte, v interface{}, keep bool) []string {
+	w := rawWalker{keep: keep, unknown: map[string]struct{}{}}
+	w.walk(data, reflect.ValueOf(v), "")
+	fields := make([]string, 0, len(w.unknown))
+	for f := range w.unknown {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func (w *rawWalker) walk(data []byte, v reflect.Value, path string) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	// Types decoding themselves, e.g. Decimal or FlexInt, are leaves
+	if v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		w.walkStruct(data, v, path)
+	case reflect.Slice, reflect.Array:
+		var elems []json.RawMessage
+		if json.Unmarshal(data, &elems) != nil {
+			return
+		}
+		for i := 0; i < len(elems) && i < v.Len(); i++ {
+			w.walk(elems[i], v.Index(i), path+"[]")
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		var elems map[string]json.RawMessage
+		if json.Unmarshal(data, &elems) != nil {
+			return
+		}
+		for k, raw := range elems {
+			key := reflect.ValueOf(k).Convert(v.Type().Key())
+			elem := v.MapIndex(key)
+			if !elem.IsValid() {
+				continue
+			}
+			// map elements are not addressable, walk a copy and store it back
+			cp := reflect.New(elem.Type()).Elem()
+			cp.Set(elem)
+			w.walk(raw, cp, joinPath(path, k))
+			v.SetMapIndex(key, cp)
+		}
+	}
+}
+
+func (w *rawWalker) walkStruct(data []byte, v reflect.Value, path string) {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(data, &obj) != nil {
+		return
+	}
+	fields := map[string]reflect.Value{}
+	var raw reflect.Value
+	collectFields(v, fields, &raw)
+
+	var unknown map[string]json.RawMessage
+	for key, val := range obj {
+		f, ok := fields[key]
+		if !ok {
+			// encoding/json matches keys case-insensitively
+			for name, candidate := range fields {
+				if strings.EqualFold(name, key) {
+					f, ok = candidate, true
+					break
+				}
+			}
+		}
+		if !ok {
+			if unknown == nil {
+				unknown = map[string]json.RawMessage{}
+			}
+			unknown[key] = val
+			w.unknown[joinPath(path, key)] = struct{}{}
+			continue
+		}
+		w.walk(val, f, joinPath(path, key))
+	}
+
+	if w.keep && raw.IsValid() && raw.CanSet() {
+		raw.Set(reflect.ValueOf(&RawFields{
+			JSON:    append(json.RawMessage(nil), data...),
+			Unknown: unknown,
+		}))
+	}
+}
+
+// collectFields maps the JSON names of the fields of struct v, including
+// the ones promoted from embedded structs, to their values, and sets raw to
+// the Raw field if v has one.
+func collectFields(v reflect.Value, fields map[string]reflect.Value, raw *reflect.Value) {
+	t := v.Type()
+	var embedded []reflect.Value
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			if sf.Type == rawFieldsType && !raw.IsValid() {
+				*raw = v.Field(i)
+			}
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if sf.Anonymous && name == "" {
+			fv := v.Field(i)
+			if fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				embedded = append(embedded, fv)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[name] = v.Field(i)
+	}
+	// promoted fields come last as the shallower ones shadow them
+	for _, fv := range embedded {
+		promoted := map[string]reflect.Value{}
+		collectFields(fv, promoted, raw)
+		for name, f := range promoted {
+			if _, ok := fields[name]; !ok {
+				fields[name] = f
+			}
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
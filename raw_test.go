@@ -0,0 +1,172 @@
+package goshopee
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeUnknown(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		v    func() interface{}
+		// raw returns the Raw field checked, if any
+		raw         func(v interface{}) *RawFields
+		wantFields  []string
+		wantUnknown []string
+	}{
+		{
+			name:       "known fields only",
+			body:       `{"ordersn":"SN1","order_status":"READY_TO_SHIP","total_amount":"12.30"}`,
+			v:          func() interface{} { return &Order{} },
+			raw:        func(v interface{}) *RawFields { return v.(*Order).Raw },
+			wantFields: []string{},
+		},
+		{
+			name:        "unknown top level field",
+			body:        `{"ordersn":"SN1","new_field":{"a":1},"other":null}`,
+			v:           func() interface{} { return &Order{} },
+			raw:         func(v interface{}) *RawFields { return v.(*Order).Raw },
+			wantFields:  []string{"new_field", "other"},
+			wantUnknown: []string{"new_field", "other"},
+		},
+		{
+			name:        "keys match case-insensitively",
+			body:        `{"OrderSN":"SN1","Order_Status":"COMPLETED","x":1}`,
+			v:           func() interface{} { return &Order{} },
+			raw:         func(v interface{}) *RawFields { return v.(*Order).Raw },
+			wantFields:  []string{"x"},
+			wantUnknown: []string{"x"},
+		},
+		{
+			name: "nested in a response",
+			body: `{"orders":[{"ordersn":"SN1"},{"ordersn":"SN2","items":[{"item_id":1,"gift":true}],"x":1}],"request_id":"r","y":2}`,
+			v:    func() interface{} { return &OrdersDetailResponse{} },
+			raw: func(v interface{}) *RawFields {
+				return v.(*OrdersDetailResponse).Orders[1].Raw
+			},
+			wantFields:  []string{"orders[].items[].gift", "orders[].x", "y"},
+			wantUnknown: []string{"x"},
+		},
+		{
+			name:        "fields promoted from an embedded struct",
+			body:        `{"item_id":1,"name":"tee","images":["a.jpg"],"video":"v.mp4"}`,
+			v:           func() interface{} { return &Item{} },
+			raw:         func(v interface{}) *RawFields { return v.(*Item).Raw },
+			wantFields:  []string{"video"},
+			wantUnknown: []string{"video"},
+		},
+		{
+			name:       "self decoding types are leaves",
+			body:       `{"ordersn":"SN1","cod":"true","days_to_ship":3,"total_amount":7}`,
+			v:          func() interface{} { return &Order{} },
+			raw:        func(v interface{}) *RawFields { return v.(*Order).Raw },
+			wantFields: []string{},
+		},
+		{
+			name:        "map values",
+			body:        `{"SN1":{"ordersn":"SN1","x":1}}`,
+			v:           func() interface{} { return &map[string]Order{} },
+			raw:         func(v interface{}) *RawFields { return (*v.(*map[string]Order))["SN1"].Raw },
+			wantFields:  []string{"SN1.x"},
+			wantUnknown: []string{"x"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := tc.v()
+			if err := json.Unmarshal([]byte(tc.body), v); err != nil {
+				t.Fatal(err)
+			}
+			fields := decodeUnknown([]byte(tc.body), v, true)
+			if !reflect.DeepEqual(fields, tc.wantFields) {
+				t.Errorf("fields = %v, want %v", fields, tc.wantFields)
+			}
+
+			raw := tc.raw(v)
+			if raw == nil {
+				t.Fatal("Raw not set")
+			}
+			if !json.Valid(raw.JSON) {
+				t.Errorf("Raw.JSON = %s, not valid JSON", raw.JSON)
+			}
+			var unknown []string
+			for k := range raw.Unknown {
+				unknown = append(unknown, k)
+			}
+			if len(unknown) != len(tc.wantUnknown) {
+				t.Errorf("Raw.Unknown = %v, want keys %v", raw.Unknown, tc.wantUnknown)
+			}
+			for _, k := range tc.wantUnknown {
+				if _, ok := raw.Unknown[k]; !ok {
+					t.Errorf("Raw.Unknown = %v, want key %s", raw.Unknown, k)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeUnknownWithoutKeep(t *testing.T) {
+	body := []byte(`{"ordersn":"SN1","x":1}`)
+	var order Order
+	if err := json.Unmarshal(body, &order); err != nil {
+		t.Fatal(err)
+	}
+	fields := decodeUnknown(body, &order, false)
+	if !reflect.DeepEqual(fields, []string{"x"}) {
+		t.Errorf("fields = %v, want [x]", fields)
+	}
+	if order.Raw != nil {
+		t.Errorf("Raw = %+v, want nil", order.Raw)
+	}
+}
+
+func TestClientRawFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"orders":[{"ordersn":"SN1","new_field":"v"}],"request_id":"r"}`))
+	}))
+	defer srv.Close()
+
+	cases := []struct {
+		name       string
+		opts       []Option
+		wantRaw    bool
+		wantDrifts int
+	}{
+		{name: "default"},
+		{name: "raw fields", opts: []Option{WithRawFields()}, wantRaw: true},
+		{name: "strict decoding", wantDrifts: 1},
+		{name: "both", opts: []Option{WithRawFields()}, wantRaw: true, wantDrifts: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var drifts []SchemaDrift
+			opts := tc.opts
+			if tc.wantDrifts > 0 {
+				opts = append(opts, WithStrictDecoding(func(d SchemaDrift) { drifts = append(drifts, d) }))
+			}
+			c := NewClient(App{APIURL: srv.URL}, opts...)
+
+			resource := new(OrdersDetailResponse)
+			if err := c.Post("/orders/detail", map[string]interface{}{}, resource); err != nil {
+				t.Fatal(err)
+			}
+			raw := resource.Orders[0].Raw
+			if (raw != nil) != tc.wantRaw {
+				t.Fatalf("Raw = %+v, want set %t", raw, tc.wantRaw)
+			}
+			if raw != nil && string(raw.Unknown["new_field"]) != `"v"` {
+				t.Errorf("Raw.Unknown = %v, want new_field", raw.Unknown)
+			}
+			if len(drifts) != tc.wantDrifts {
+				t.Fatalf("%d drifts, want %d", len(drifts), tc.wantDrifts)
+			}
+			if len(drifts) > 0 && !reflect.DeepEqual(drifts[0].Fields, []string{"orders[].new_field"}) {
+				t.Errorf("drift fields = %v, want [orders[].new_field]", drifts[0].Fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package goshopee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// defaultShipConcurrency is the number of orders a BatchShipper arranges at
+// once.
+const defaultShipConcurrency = 4
+
+// ShippingPolicy builds the LogisticService.Init request of an order from
+// the parameters Shopee requires and the logistics info of the order. info
+// is nil when neither pickup nor dropoff is available.
+type ShippingPolicy interface {
+	InitRequest(order *Order, params *GetParameterForInitResponse, info *GetLogisticInfoResponse) (LogisticInitRequest, error)
+}
+
+// ShippingPolicyFunc adapts a function to a ShippingPolicy.
+type ShippingPolicyFunc func(order *Order, params *GetParameterForInitResponse, info *GetLogisticInfoResponse) (LogisticInitRequest, error)
+
+func (f ShippingPolicyFunc) InitRequest(order *Order, params *GetParameterForInitResponse, info *GetLogisticInfoResponse) (LogisticInitRequest, error) {
+	return f(order, params, info)
+}
+
+// DefaultShippingPolicy books a pickup when available, at the earliest time
+// slot of the pickup address, and otherwise drops parcels off at a branch.
+// Orders of channels not integrated with Shopee need NonIntegratedTrackingNo.
+type DefaultShippingPolicy struct {
+	// PickupAddressID is the pickup address to use, defaults to the first
+	// address with a time slot.
+	PickupAddressID uint64
+
+	// PreferDropoff drops parcels off rather than booking a pickup when an
+	// order supports both.
+	PreferDropoff bool
+
+	// DropoffBranchID is the branch to drop parcels off at, defaults to the
+	// first branch.
+	DropoffBranchID uint64
+
+	// SenderRealName is sent with dropoffs requiring it.
+	SenderRealName string
+
+	// NonIntegratedTrackingNo returns the tracking number of an order shipped
+	// with a channel not integrated with Shopee.
+	NonIntegratedTrackingNo func(order *Order) (string, error)
+}
+
+// InitRequest implements ShippingPolicy.
+func (p DefaultShippingPolicy) InitRequest(order *Order, params *GetParameterForInitResponse, info *GetLogisticInfoResponse) (LogisticInitRequest, error) {
+	_, pickup := params.Required(LogisticInitPickup)
+	_, dropoff := params.Required(LogisticInitDropoff)
+	_, nonIntegrated := params.Required(LogisticInitNonIntegrated)
+	switch {
+	case dropoff && (p.PreferDropoff || !pickup):
+		return p.dropoff(info)
+	case pickup:
+		return p.pickup(info)
+	case nonIntegrated:
+		if p.NonIntegratedTrackingNo == nil {
+			return LogisticInitRequest{}, errors.New("non_integrated shipping needs a tracking number, see DefaultShippingPolicy.NonIntegratedTrackingNo")
+		}
+		trackingNo, err := p.NonIntegratedTrackingNo(order)
+		if err != nil {
+			return LogisticInitRequest{}, err
+		}
+		return LogisticInitRequest{NonIntegrated: &LogisticInitNonIntegratedParams{TrackingNo: trackingNo}}, nil
+	}
+	return LogisticInitRequest{}, errors.New("no shipping method available")
+}
+
+func (p DefaultShippingPolicy) pickup(info *GetLogisticInfoResponse) (LogisticInitRequest, error) {
+	if info == nil {
+		return LogisticInitRequest{}, errors.New("no pickup address")
+	}
+	var address *Address
+	for i, a := range info.Pickup.AddressList {
+		if p.PickupAddressID != 0 && a.AddressID == p.PickupAddressID {
+			address = &info.Pickup.AddressList[i]
+			break
+		}
+		if p.PickupAddressID == 0 && len(a.TimeSlotList) > 0 {
+			address = &info.Pickup.AddressList[i]
+			break
+		}
+	}
+	if address == nil {
+		if p.PickupAddressID != 0 {
+			return LogisticInitRequest{}, fmt.Errorf("pickup address %d not available", p.PickupAddressID)
+		}
+		return LogisticInitRequest{}, errors.New("no pickup address with a time slot")
+	}
+	if len(address.TimeSlotList) == 0 {
+		return LogisticInitRequest{}, fmt.Errorf("no pickup time slot for address %d", address.AddressID)
+	}
+	earliest := address.TimeSlotList[0]
+	for _, slot := range address.TimeSlotList[1:] {
+		if slot.Date < earliest.Date {
+			earliest = slot
+		}
+	}
+	return LogisticInitRequest{Pickup: &LogisticInitPickupParams{
+		AddressID:    address.AddressID,
+		PickupTimeID: earliest.PickupTimeID,
+	}}, nil
+}
+
+func (p DefaultShippingPolicy) dropoff(info *GetLogisticInfoResponse) (LogisticInitRequest, error) {
+	req := LogisticInitRequest{Dropoff: &LogisticInitDropoffParams{SenderRealName: p.SenderRealName}}
+	if info == nil || len(info.Dropoff.BranchList) == 0 {
+		if p.DropoffBranchID != 0 {
+			return LogisticInitRequest{}, fmt.Errorf("dropoff branch %d not available, the order lists no branch", p.DropoffBranchID)
+		}
+		// some channels take parcels at any of their branches
+		return req, nil
+	}
+	req.Dropoff.BranchID = info.Dropoff.BranchList[0].BranchID
+	if p.DropoffBranchID != 0 {
+		req.Dropoff.BranchID = 0
+		for _, b := range info.Dropoff.BranchList {
+			if b.BranchID == p.DropoffBranchID {
+				req.Dropoff.BranchID = b.BranchID
+			}
+		}
+		if req.Dropoff.BranchID == 0 {
+			return LogisticInitRequest{}, fmt.Errorf("dropoff branch %d not available", p.DropoffBranchID)
+		}
+	}
+	return req, nil
+}
+
+// ShipmentResult is the outcome of arranging the shipment of one order.
+type ShipmentResult struct {
+	OrderSN string
+
+	// Method is how the parcel gets to the carrier.
+	Method LogisticInitMethod
+
+	// TrackingNumber may be empty for channels assigning it asynchronously,
+	// unless the shipper waits for it, see BatchShipper.WaitTracking.
+	TrackingNumber string
+
+	// AlreadyArranged is set for orders whose shipment was arranged before,
+	// including the ones still waiting for their tracking number, they are
+	// reported with their tracking number and not arranged again.
+	AlreadyArranged bool
+
+	// Err is why the shipment could not be arranged, nil on success.
+	Err error
+}
+
+// BatchShipper arranges the shipment of many orders of a shop: for each
+// order it checks the order can still be shipped, resolves the parameters
+// Shopee requires, builds the request with the Policy and calls Init.
+type BatchShipper struct {
+	client *Client
+	shopID uint64
+
+	// Policy chooses how each order is shipped.
+	Policy ShippingPolicy
+
+	// Concurrency is the number of orders arranged at once, defaults to 4.
+	Concurrency int
+
+	// WaitTracking, when not nil, makes Ship wait for the tracking numbers
+	// assigned asynchronously, polling with these options.
+	WaitTracking *TrackingPollOptions
+}
+
+// NewBatchShipper returns a BatchShipper for shop sid using policy, nil for
+// DefaultShippingPolicy.
+func NewBatchShipper(client *Client, sid uint64, policy ShippingPolicy) *BatchShipper {
+	if policy == nil {
+		policy = DefaultShippingPolicy{}
+	}
+	return &BatchShipper{
+		client:      client,
+		shopID:      sid,
+		Policy:      policy,
+		Concurrency: defaultShipConcurrency,
+	}
+}
+
+// Ship arranges the shipment of ordersn and returns a result per distinct
+// order, in the order given. Failing orders do not stop the others; an error
+// is only returned when the orders cannot be fetched or ctx is done, the
+// orders not attempted then fail with the same error.
+func (b *BatchShipper) Ship(ctx context.Context, ordersn []string) ([]ShipmentResult, error) {
+	var unique []string
+	seen := map[string]bool{}
+	for _, sn := range ordersn {
+		if !seen[sn] {
+			seen[sn] = true
+			unique = append(unique, sn)
+		}
+	}
+	results := make([]ShipmentResult, len(unique))
+	for i, sn := range unique {
+		results[i].OrderSN = sn
+	}
+	if len(unique) == 0 {
+		return results, nil
+	}
+
+	orders, missing, err := b.client.Order.GetMulti(ctx, b.shopID, unique)
+	if err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+		return results, err
+	}
+	byOrderSN := make(map[string]*Order, len(orders))
+	for i := range orders {
+		byOrderSN[orders[i].OrderSN] = &orders[i]
+	}
+	for _, sn := range missing {
+		delete(byOrderSN, sn)
+	}
+
+	concurrency := b.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultShipConcurrency
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := range results {
+		order, ok := byOrderSN[results[i].OrderSN]
+		if !ok {
+			results[i].Err = fmt.Errorf("order %s not found", results[i].OrderSN)
+			continue
+		}
+		if ctx.Err() != nil {
+			results[i].Err = ctx.Err()
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(r *ShipmentResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			b.ship(ctx, order, r)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results, ctx.Err()
+}
+
+// ship arranges the shipment of order, filling in r.
+func (b *BatchShipper) ship(ctx context.Context, order *Order, r *ShipmentResult) {
+	if order.TrackingNo != "" {
+		r.TrackingNumber = order.TrackingNo
+		r.AlreadyArranged = true
+		return
+	}
+	if r.Err = order.CheckAction(OrderActionInitLogistics); r.Err != nil {
+		return
+	}
+	if b.initialized(order) {
+		r.AlreadyArranged = true
+		b.waitTracking(ctx, order, r)
+		return
+	}
+	logistic := b.client.Logistic
+	params, err := logistic.GetParameterForInit(b.shopID, order.OrderSN)
+	if err != nil {
+		r.Err = fmt.Errorf("get parameters for init: %w", err)
+		return
+	}
+	if params == nil {
+		params = &GetParameterForInitResponse{}
+	}
+	var info *GetLogisticInfoResponse
+	if params.Pickup != nil || params.Dropoff != nil {
+		if info, err = logistic.GetLogisticInfo(b.shopID, order.OrderSN); err != nil {
+			r.Err = fmt.Errorf("get logistic info: %w", err)
+			return
+		}
+	}
+	req, err := b.Policy.InitRequest(order, params, info)
+	if err != nil {
+		r.Err = fmt.Errorf("shipping policy: %w", err)
+		return
+	}
+	r.Method, _ = req.Method()
+	resp, err := logistic.Init(b.shopID, order.OrderSN, req, params)
+	if err != nil {
+		r.Err = fmt.Errorf("init: %w", err)
+		return
+	}
+	if resp != nil {
+		r.TrackingNumber = resp.TrackingNumber
+	}
+	b.waitTracking(ctx, order, r)
+}
+
+// initialized reports whether the logistics of order, which has no tracking
+// number yet, were initialized already, as with channels assigning tracking
+// numbers asynchronously.
+func (b *BatchShipper) initialized(order *Order) bool {
+	info, err := b.client.Logistic.GetTrackingInfo(b.shopID, order.OrderSN)
+	if err != nil {
+		// Init fails in turn if the logistics were initialized
+		b.client.log.Debugf("get logistics status of order %s: %s", order.OrderSN, err)
+		return false
+	}
+	return info != nil && info.LogisticsStatus == LogisticsStatusRequestCreated
+}
+
+// waitTracking waits for the tracking number of order when it is not known
+// yet and the shipper is set to.
+func (b *BatchShipper) waitTracking(ctx context.Context, order *Order, r *ShipmentResult) {
+	if r.TrackingNumber == "" && b.WaitTracking != nil {
+		r.TrackingNumber, r.Err = b.client.Logistic.WaitTrackingNumber(ctx, b.shopID, order.OrderSN, b.WaitTracking)
+	}
+}
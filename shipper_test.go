@@ -0,0 +1,95 @@
+package goshopee_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+)
+
+func TestDefaultShippingPolicyDropoff(t *testing.T) {
+	dropoff := &goshopee.GetParameterForInitResponse{Dropoff: []string{}}
+	branches := &goshopee.GetLogisticInfoResponse{Dropoff: goshopee.GetLogisticInfoResponseDropoff{
+		BranchList: []goshopee.Branch{{BranchID: 10}, {BranchID: 20}},
+	}}
+	cases := []struct {
+		name       string
+		policy     goshopee.DefaultShippingPolicy
+		info       *goshopee.GetLogisticInfoResponse
+		wantBranch uint64
+		wantErr    bool
+	}{
+		{name: "first branch", info: branches, wantBranch: 10},
+		{name: "preferred branch", policy: goshopee.DefaultShippingPolicy{DropoffBranchID: 20}, info: branches, wantBranch: 20},
+		{name: "preferred branch not listed", policy: goshopee.DefaultShippingPolicy{DropoffBranchID: 30}, info: branches, wantErr: true},
+		{name: "any branch", info: &goshopee.GetLogisticInfoResponse{}},
+		{name: "no logistics info", info: nil},
+		{name: "preferred branch without branch list", policy: goshopee.DefaultShippingPolicy{DropoffBranchID: 20}, info: &goshopee.GetLogisticInfoResponse{}, wantErr: true},
+		{name: "preferred branch without logistics info", policy: goshopee.DefaultShippingPolicy{DropoffBranchID: 20}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := tc.policy.InitRequest(&goshopee.Order{OrderSN: "SN1"}, dropoff, tc.info)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("InitRequest error = %v, want error %t", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if req.Dropoff == nil || req.Dropoff.BranchID != tc.wantBranch {
+				t.Errorf("InitRequest = %+v, want dropoff at branch %d", req.Dropoff, tc.wantBranch)
+			}
+		})
+	}
+}
+
+func TestBatchShipperRerun(t *testing.T) {
+	cases := []struct {
+		name          string
+		trackingDelay int
+		// wantTracking is the tracking number of the first run
+		wantTracking string
+	}{
+		{name: "tracking number on init", wantTracking: "TNSN1"},
+		{name: "tracking number assigned later", trackingDelay: 100},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := goshopeetest.NewServer(1, "key")
+			defer srv.Close()
+			srv.SetTrackingDelay(tc.trackingDelay)
+			srv.AddOrder(7, goshopee.Order{OrderSN: "SN1"})
+			shipper := goshopee.NewBatchShipper(srv.Client(), 7, nil)
+
+			first, err := shipper.Ship(context.Background(), []string{"SN1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := goshopee.ShipmentResult{OrderSN: "SN1", Method: goshopee.LogisticInitPickup, TrackingNumber: tc.wantTracking}
+			if !reflect.DeepEqual(first[0], want) {
+				t.Errorf("first run = %+v, want %+v", first[0], want)
+			}
+
+			second, err := shipper.Ship(context.Background(), []string{"SN1", "SN1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want = goshopee.ShipmentResult{OrderSN: "SN1", TrackingNumber: tc.wantTracking, AlreadyArranged: true}
+			if len(second) != 1 || !reflect.DeepEqual(second[0], want) {
+				t.Errorf("second run = %+v, want %+v", second, want)
+			}
+
+			inits := 0
+			for _, r := range srv.Requests() {
+				if r.Path == "/logistics/init" {
+					inits++
+				}
+			}
+			if inits != 1 {
+				t.Errorf("%d calls to init, want 1", inits)
+			}
+		})
+	}
+}
@@ -30,16 +30,19 @@ type Shop struct {
 	Videos              []string           `json:"videos"`
 	Images              []string           `json:"images"`
 	DisableMakeOffer    uint32             `json:"disable_make_offer"`
-	EnableDisplayUnitNo bool               `json:"enable_display_unitno"`
+	EnableDisplayUnitNo FlexBool           `json:"enable_display_unitno"`
 	ItemLimit           uint32             `json:"item_limit"`
 	RequestID           string             `json:"request_id"`
 	Status              string             `json:"status"`
 	InstallmentStatus   uint32             `json:"installment_status"`
 	SIPAffiliateShops   []SIPAffiliateShop `json:"sip_a_shops"`
-	IsCB                bool               `json:"is_cb"`
+	IsCB                FlexBool           `json:"is_cb"`
 	NonPreOrderDTS      int                `json:"non_pre_order_dts"`
 	AuthTime            int64              `json:"auth_time"`
 	ExpireTime          int64              `json:"expire_time"`
+
+	// Raw is only set with WithRawFields.
+	Raw *RawFields `json:"-"`
 }
 
 type SIPAffiliateShop struct {
@@ -0,0 +1,258 @@
+package goshopee
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSLALookback    = 30 * 24 * time.Hour
+	defaultSLAAlertWithin = 24 * time.Hour
+)
+
+// countryTimezones maps the Shopee countries to the timezone their shops
+// operate in.
+var countryTimezones = map[string]string{
+	"SG": "Asia/Singapore",
+	"MY": "Asia/Kuala_Lumpur",
+	"TH": "Asia/Bangkok",
+	"ID": "Asia/Jakarta",
+	"VN": "Asia/Ho_Chi_Minh",
+	"PH": "Asia/Manila",
+	"TW": "Asia/Taipei",
+	"BR": "America/Sao_Paulo",
+	"MX": "America/Mexico_City",
+	"CO": "America/Bogota",
+	"CL": "America/Santiago",
+	"PL": "Europe/Warsaw",
+	"ES": "Europe/Madrid",
+	"FR": "Europe/Paris",
+	"IN": "Asia/Kolkata",
+}
+
+// CountryLocation returns the timezone of the shops of a country, e.g.
+// Asia/Singapore for SG. Programs running without the system timezone
+// database should import time/tzdata.
+func CountryLocation(country string) (*time.Location, error) {
+	name, ok := countryTimezones[strings.ToUpper(country)]
+	if !ok {
+		return nil, fmt.Errorf("unknown country %q", country)
+	}
+	return time.LoadLocation(name)
+}
+
+// SLAClass classifies an open order by the time left to ship it.
+type SLAClass string
+
+const (
+	SLAOverdue     SLAClass = "overdue"      // ShipByDate passed
+	SLADueToday    SLAClass = "due_today"    // ShipByDate before the end of the day
+	SLADueTomorrow SLAClass = "due_tomorrow" // ShipByDate before the end of tomorrow
+	SLAOnTrack     SLAClass = "on_track"     // ShipByDate later
+	SLANoDeadline  SLAClass = "no_deadline"  // no ShipByDate yet
+)
+
+// SLAOrder is an open order and its deadline.
+type SLAOrder struct {
+	OrderSN string
+	Status  OrderStatus
+
+	// ShipBy is the ShipByDate in the shop timezone.
+	ShipBy time.Time
+
+	// Remaining is the time left until ShipBy, negative once overdue.
+	Remaining time.Duration
+
+	Class SLAClass
+
+	// Arranged is set when the shipment was arranged, the order has a
+	// tracking number but the parcel was not handed over yet.
+	Arranged bool
+}
+
+// SLAAlert is an order approaching or past its deadline.
+type SLAAlert struct {
+	ShopID uint64
+	SLAOrder
+}
+
+// SLAReport is the outcome of an SLA scan.
+type SLAReport struct {
+	ShopID      uint64
+	GeneratedAt time.Time
+	Location    *time.Location
+
+	// Orders are the open orders, the earliest deadline first.
+	Orders []SLAOrder
+
+	// Counts is the number of orders per class.
+	Counts map[SLAClass]int
+}
+
+// ByClass returns the orders of class c, the earliest deadline first.
+func (r *SLAReport) ByClass(c SLAClass) []SLAOrder {
+	var orders []SLAOrder
+	for _, o := range r.Orders {
+		if o.Class == c {
+			orders = append(orders, o)
+		}
+	}
+	return orders
+}
+
+// SLAMonitor scans the orders of a shop waiting to be shipped and alerts on
+// the ones close to or past their ShipByDate, after which Shopee applies
+// late shipment penalties. Days are counted in the shop timezone.
+type SLAMonitor struct {
+	client *Client
+	shopID uint64
+	alert  func(context.Context, SLAAlert)
+
+	// Location is the shop timezone, defaults to the timezone of the shop
+	// country.
+	Location *time.Location
+
+	// Lookback is how far back the orders created are scanned, defaults to
+	// 30 days.
+	Lookback time.Duration
+
+	// AlertWithin alerts on the orders due within this duration, defaults
+	// to 24 hours. Overdue orders are always alerted on.
+	AlertWithin time.Duration
+}
+
+// NewSLAMonitor returns an SLAMonitor for shop sid passing its alerts to
+// alert, which may be nil to only build reports.
+func NewSLAMonitor(client *Client, sid uint64, alert func(context.Context, SLAAlert)) *SLAMonitor {
+	return &SLAMonitor{
+		client:      client,
+		shopID:      sid,
+		alert:       alert,
+		Lookback:    defaultSLALookback,
+		AlertWithin: defaultSLAAlertWithin,
+	}
+}
+
+// Run scans every interval until ctx is done. Failed scans are logged and
+// retried at the next interval.
+func (m *SLAMonitor) Run(ctx context.Context, interval time.Duration) error {
+	for {
+		if _, err := m.Scan(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			m.client.log.Errorf("scan order deadlines of shop %d: %s", m.shopID, err)
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// Scan classifies the orders of the shop waiting to be shipped, alerts on
+// the ones at risk and returns the report.
+func (m *SLAMonitor) Scan(ctx context.Context) (*SLAReport, error) {
+	loc, err := m.location()
+	if err != nil {
+		return nil, err
+	}
+	now := m.client.now().In(loc)
+
+	var ordersn []string
+	for _, status := range []OrderStatus{OrderStatusReadyToShip, OrderStatusRetryShip} {
+		query := OrderQuery{From: now.Add(-m.Lookback), To: now, Status: status}
+		for order, err := range m.client.Order.Query(ctx, m.shopID, query) {
+			if err != nil {
+				return nil, err
+			}
+			ordersn = append(ordersn, order.OrderSN)
+		}
+	}
+
+	report := &SLAReport{
+		ShopID:      m.shopID,
+		GeneratedAt: now,
+		Location:    loc,
+		Counts:      map[SLAClass]int{},
+	}
+	if len(ordersn) > 0 {
+		orders, _, err := m.client.Order.GetMulti(ctx, m.shopID, ordersn)
+		if err != nil {
+			return nil, err
+		}
+		for i := range orders {
+			if orders[i].Status != OrderStatusReadyToShip && orders[i].Status != OrderStatusRetryShip {
+				// shipped since it was listed
+				continue
+			}
+			report.Orders = append(report.Orders, classifySLA(&orders[i], now))
+		}
+	}
+	sort.SliceStable(report.Orders, func(i, j int) bool {
+		a, b := report.Orders[i], report.Orders[j]
+		if a.ShipBy.IsZero() != b.ShipBy.IsZero() {
+			return b.ShipBy.IsZero()
+		}
+		return a.ShipBy.Before(b.ShipBy)
+	})
+
+	for _, o := range report.Orders {
+		report.Counts[o.Class]++
+		if m.alert != nil && o.Class != SLANoDeadline && o.Remaining <= m.AlertWithin {
+			m.alert(ctx, SLAAlert{ShopID: m.shopID, SLAOrder: o})
+		}
+	}
+	return report, nil
+}
+
+// location returns the shop timezone, looking it up from the shop country
+// the first time.
+func (m *SLAMonitor) location() (*time.Location, error) {
+	if m.Location != nil {
+		return m.Location, nil
+	}
+	shop, err := m.client.Shop.Get(m.shopID)
+	if err != nil {
+		return nil, fmt.Errorf("get shop timezone: %w", err)
+	}
+	loc, err := CountryLocation(shop.Country)
+	if err != nil {
+		return nil, fmt.Errorf("get shop timezone: %w", err)
+	}
+	m.Location = loc
+	return loc, nil
+}
+
+// classifySLA classifies order at now, days start at midnight in the
+// location of now.
+func classifySLA(order *Order, now time.Time) SLAOrder {
+	o := SLAOrder{
+		OrderSN:  order.OrderSN,
+		Status:   order.Status,
+		Arranged: order.TrackingNo != "",
+		Class:    SLANoDeadline,
+	}
+	if order.ShipByDate == 0 {
+		return o
+	}
+	o.ShipBy = order.ShipBy().In(now.Location())
+	o.Remaining = o.ShipBy.Sub(now)
+
+	y, mo, d := now.Date()
+	endOfToday := time.Date(y, mo, d+1, 0, 0, 0, 0, now.Location())
+	endOfTomorrow := time.Date(y, mo, d+2, 0, 0, 0, 0, now.Location())
+	switch {
+	case o.Remaining < 0:
+		o.Class = SLAOverdue
+	case o.ShipBy.Before(endOfToday):
+		o.Class = SLADueToday
+	case o.ShipBy.Before(endOfTomorrow):
+		o.Class = SLADueTomorrow
+	default:
+		o.Class = SLAOnTrack
+	}
+	return o
+}
@@ -0,0 +1,136 @@
+package goshopee_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	goshopee "github.com/passwind/go-shopee"
+	"github.com/passwind/go-shopee/goshopeetest"
+	"github.com/passwind/go-shopee/mocks"
+)
+
+// newSLATest returns a fake server and a client at 23:30 in Singapore, with
+// orders of shop 7 in every SLA class. GONE is listed as ready to ship but
+// ships before its details are fetched.
+func newSLATest(t *testing.T) (*goshopeetest.Server, *goshopee.Client, time.Time) {
+	t.Helper()
+	loc, err := time.LoadLocation("Asia/Singapore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, time.March, 11, 23, 30, 0, 0, loc)
+	clock := goshopee.ClockFunc(func() time.Time { return now })
+
+	srv := goshopeetest.NewServer(1, "key")
+	srv.SetClock(clock)
+	srv.AddShop(goshopee.Shop{ID: 7, Country: "SG"})
+	created := now.Add(-time.Hour).Unix()
+	for _, o := range []goshopee.Order{
+		{OrderSN: "OVERDUE", ShipByDate: now.Add(-time.Hour).Unix()},
+		{OrderSN: "SOON", ShipByDate: now.Add(10 * time.Minute).Unix()},
+		{OrderSN: "TOMORROW", ShipByDate: now.Add(2 * time.Hour).Unix()},
+		{OrderSN: "RETRY", Status: goshopee.OrderStatusRetryShip, ShipByDate: now.Add(30 * time.Hour).Unix()},
+		{OrderSN: "NEXT_WEEK", ShipByDate: now.Add(7 * 24 * time.Hour).Unix()},
+		{OrderSN: "NONE"},
+		{OrderSN: "SHIPPED", Status: goshopee.OrderStatusShipped, ShipByDate: now.Add(-time.Hour).Unix()},
+		{OrderSN: "GONE", ShipByDate: now.Add(-time.Hour).Unix()},
+	} {
+		o.CreateTime = created
+		srv.AddOrder(7, o)
+	}
+
+	client := srv.Client(goshopee.WithClock(clock))
+	orders := client.Order
+	client.Order = &mocks.OrderService{
+		QueryFunc: orders.Query,
+		GetMultiFunc: func(ctx context.Context, sid uint64, ordersn []string) ([]goshopee.Order, []string, error) {
+			srv.SetOrderStatus(7, "GONE", goshopee.OrderStatusShipped)
+			return orders.GetMulti(ctx, sid, ordersn)
+		},
+	}
+	return srv, client, now
+}
+
+func TestSLAMonitorScan(t *testing.T) {
+	srv, client, now := newSLATest(t)
+	defer srv.Close()
+
+	report, err := goshopee.NewSLAMonitor(client, 7, nil).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if report.Location.String() != "Asia/Singapore" || !report.GeneratedAt.Equal(now) {
+		t.Errorf("report at %s in %s, want %s in Asia/Singapore", report.GeneratedAt, report.Location, now)
+	}
+
+	type class struct {
+		OrderSN string
+		Class   goshopee.SLAClass
+	}
+	var got []class
+	for _, o := range report.Orders {
+		got = append(got, class{o.OrderSN, o.Class})
+	}
+	// the shipped orders are skipped, the others sorted by deadline
+	want := []class{
+		{"OVERDUE", goshopee.SLAOverdue},
+		{"SOON", goshopee.SLADueToday},
+		{"TOMORROW", goshopee.SLADueTomorrow},
+		{"RETRY", goshopee.SLAOnTrack},
+		{"NEXT_WEEK", goshopee.SLAOnTrack},
+		{"NONE", goshopee.SLANoDeadline},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Orders = %v, want %v", got, want)
+	}
+	wantCounts := map[goshopee.SLAClass]int{
+		goshopee.SLAOverdue:     1,
+		goshopee.SLADueToday:    1,
+		goshopee.SLADueTomorrow: 1,
+		goshopee.SLAOnTrack:     2,
+		goshopee.SLANoDeadline:  1,
+	}
+	if !reflect.DeepEqual(report.Counts, wantCounts) {
+		t.Errorf("Counts = %v, want %v", report.Counts, wantCounts)
+	}
+}
+
+func TestSLAMonitorAlertWithin(t *testing.T) {
+	cases := []struct {
+		name        string
+		alertWithin time.Duration
+		want        []string
+	}{
+		{"overdue only", 0, []string{"OVERDUE"}},
+		{"below a deadline", 9 * time.Minute, []string{"OVERDUE"}},
+		{"at a deadline", 10 * time.Minute, []string{"OVERDUE", "SOON"}},
+		{"default", -1, []string{"OVERDUE", "SOON", "TOMORROW"}},
+		{"two days", 48 * time.Hour, []string{"OVERDUE", "SOON", "TOMORROW", "RETRY"}},
+		{"a month", 30 * 24 * time.Hour, []string{"OVERDUE", "SOON", "TOMORROW", "RETRY", "NEXT_WEEK"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, client, _ := newSLATest(t)
+			defer srv.Close()
+
+			var got []string
+			monitor := goshopee.NewSLAMonitor(client, 7, func(ctx context.Context, a goshopee.SLAAlert) {
+				if a.ShopID != 7 {
+					t.Errorf("alert for shop %d, want 7", a.ShopID)
+				}
+				got = append(got, a.OrderSN)
+			})
+			if tc.alertWithin >= 0 {
+				monitor.AlertWithin = tc.alertWithin
+			}
+			if _, err := monitor.Scan(context.Background()); err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("alerts = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package goshopee
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassifySLA(t *testing.T) {
+	// Singapore is UTC+8: early in the morning the shop day is one ahead of
+	// the UTC day, late in the evening the next shop day starts before the
+	// next UTC day.
+	loc, err := time.LoadLocation("Asia/Singapore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2024, time.March, day, hour, min, 0, 0, loc)
+	}
+	morning := at(11, 0, 30) // March 10 in UTC
+	evening := at(11, 23, 30)
+
+	cases := []struct {
+		name   string
+		now    time.Time
+		shipBy time.Time
+		want   SLAClass
+	}{
+		{"overdue since midnight", morning, at(11, 0, 0), SLAOverdue},
+		{"overdue since yesterday", morning, at(10, 12, 0), SLAOverdue},
+		{"overdue by a second", evening, at(11, 23, 30).Add(-time.Second), SLAOverdue},
+		{"due now", evening, evening, SLADueToday},
+		{"due today on the next UTC day", morning, at(11, 23, 59), SLADueToday},
+		{"due today late evening", evening, at(11, 23, 45), SLADueToday},
+		{"due tomorrow at midnight", morning, at(12, 0, 0), SLADueTomorrow},
+		{"due tomorrow on the same UTC day", evening, at(12, 7, 0), SLADueTomorrow},
+		{"due tomorrow late evening", morning, at(12, 23, 59), SLADueTomorrow},
+		{"on track at midnight", morning, at(13, 0, 0), SLAOnTrack},
+		{"on track on the next UTC day", evening, at(13, 7, 0), SLAOnTrack},
+		{"no deadline", morning, time.Time{}, SLANoDeadline},
+		{"no deadline late evening", evening, time.Time{}, SLANoDeadline},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			order := &Order{OrderSN: "SN1", Status: OrderStatusReadyToShip}
+			if !tc.shipBy.IsZero() {
+				order.ShipByDate = tc.shipBy.Unix()
+			}
+			// classifySLA counts days in the location of now, not UTC
+			got := classifySLA(order, tc.now)
+			if got.Class != tc.want {
+				t.Errorf("Class = %s, want %s", got.Class, tc.want)
+			}
+			if tc.shipBy.IsZero() {
+				if !got.ShipBy.IsZero() || got.Remaining != 0 {
+					t.Errorf("ShipBy = %s, Remaining = %s, want none", got.ShipBy, got.Remaining)
+				}
+				return
+			}
+			if !got.ShipBy.Equal(tc.shipBy) || got.ShipBy.Location() != loc {
+				t.Errorf("ShipBy = %s, want %s", got.ShipBy, tc.shipBy)
+			}
+			if want := tc.shipBy.Sub(tc.now); got.Remaining != want {
+				t.Errorf("Remaining = %s, want %s", got.Remaining, want)
+			}
+		})
+	}
+}
+
+func TestClassifySLAArranged(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+	cases := []struct {
+		trackingNo string
+		want       bool
+	}{
+		{"", false},
+		{"TN1", true},
+	}
+	for _, tc := range cases {
+		order := &Order{OrderSN: "SN1", Status: OrderStatusRetryShip, TrackingNo: tc.trackingNo}
+		got := classifySLA(order, now)
+		if got.Arranged != tc.want || got.OrderSN != "SN1" || got.Status != OrderStatusRetryShip {
+			t.Errorf("classifySLA with tracking number %q = %+v, want arranged %t", tc.trackingNo, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package goshopee
+
+import "time"
+
+// Shopee sends every timestamp as seconds since the Unix epoch, in uint32 or
+// int64 fields depending on the model. The helpers below convert them to
+// time.Time; an unset (0) timestamp converts to the zero time.Time so callers
+// can test it with IsZero.
+
+// unixToTime converts a Shopee epoch field to time.Time.
+func unixToTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// CreatedAt returns CreateTime as a time.Time.
+func (o *Order) CreatedAt() time.Time {
+	return unixToTime(o.CreateTime)
+}
+
+// UpdatedAt returns UpdateTime as a time.Time.
+func (o *Order) UpdatedAt() time.Time {
+	return unixToTime(o.UpdateTime)
+}
+
+// PaidAt returns PayTime as a time.Time, zero while the order is unpaid.
+func (o *Order) PaidAt() time.Time {
+	if o.PayTime == nil {
+		return time.Time{}
+	}
+	return unixToTime(*o.PayTime)
+}
+
+// ShipBy returns ShipByDate, the deadline to ship the order, as a time.Time.
+func (o *Order) ShipBy() time.Time {
+	return unixToTime(o.ShipByDate)
+}
+
+// NoteUpdatedAt returns NoteUpdateTime as a time.Time.
+func (o *Order) NoteUpdatedAt() time.Time {
+	return unixToTime(o.NoteUpdateTime)
+}
+
+// CreatedAt returns CreateTime as a time.Time.
+func (i *ItemBase) CreatedAt() time.Time {
+	return unixToTime(int64(i.CreateTime))
+}
+
+// UpdatedAt returns UpdateTime as a time.Time.
+func (i *ItemBase) UpdatedAt() time.Time {
+	return unixToTime(int64(i.UpdateTime))
+}
+
+// CreatedAt returns CreateTime as a time.Time.
+func (v *Variation) CreatedAt() time.Time {
+	return unixToTime(int64(v.CreateTime))
+}
+
+// UpdatedAt returns UpdateTime as a time.Time.
+func (v *Variation) UpdatedAt() time.Time {
+	return unixToTime(int64(v.UpdateTime))
+}
+
+// Time returns the start of the slot as a time.Time.
+func (t TimeSlot) Time() time.Time {
+	return unixToTime(t.Date)
+}
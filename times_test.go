@@ -0,0 +1,54 @@
+package goshopee
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnixToTime(t *testing.T) {
+	if got := unixToTime(0); !got.IsZero() {
+		t.Errorf("unixToTime(0) = %s, want the zero time", got)
+	}
+	if got := unixToTime(1700000000); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unixToTime(1700000000) = %s", got)
+	}
+}
+
+func TestOrderTimes(t *testing.T) {
+	paid := int64(1700000600)
+	order := &Order{
+		CreateTime:     1700000000,
+		UpdateTime:     1700000900,
+		PayTime:        &paid,
+		ShipByDate:     1700259200,
+		NoteUpdateTime: 0,
+	}
+	cases := []struct {
+		name string
+		got  time.Time
+		want int64
+	}{
+		{"CreatedAt", order.CreatedAt(), 1700000000},
+		{"UpdatedAt", order.UpdatedAt(), 1700000900},
+		{"PaidAt", order.PaidAt(), 1700000600},
+		{"ShipBy", order.ShipBy(), 1700259200},
+		{"NoteUpdatedAt", order.NoteUpdatedAt(), 0},
+		{"PaidAt unpaid", (&Order{}).PaidAt(), 0},
+		{"ItemBase.CreatedAt", (&ItemBase{CreateTime: 1700000000}).CreatedAt(), 1700000000},
+		{"ItemBase.UpdatedAt", (&ItemBase{UpdateTime: 1700000900}).UpdatedAt(), 1700000900},
+		{"Variation.CreatedAt", (&Variation{CreateTime: 1700000000}).CreatedAt(), 1700000000},
+		{"Variation.UpdatedAt", (&Variation{}).UpdatedAt(), 0},
+		{"TimeSlot.Time", TimeSlot{Date: 1700000000}.Time(), 1700000000},
+	}
+	for _, tc := range cases {
+		if tc.want == 0 {
+			if !tc.got.IsZero() {
+				t.Errorf("%s = %s, want the zero time", tc.name, tc.got)
+			}
+			continue
+		}
+		if tc.got.Unix() != tc.want {
+			t.Errorf("%s = %d, want %d", tc.name, tc.got.Unix(), tc.want)
+		}
+	}
+}
@@ -1,14 +1,21 @@
 package goshopee
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+)
 
 func ToMapData(in interface{}) (map[string]interface{}, error) {
 	byts, err := json.Marshal(in)
 	if err != nil {
 		return nil, err
 	}
+	// decode numbers as json.Number so prices and ids are sent back
+	// exactly as they were encoded
 	var result map[string]interface{}
-	if err := json.Unmarshal(byts, &result); err != nil {
+	dec := json.NewDecoder(bytes.NewReader(byts))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
 		return nil, err
 	}
 	return result, nil
@@ -5,25 +5,28 @@ type Variation struct {
 	Name                  string   `json:"name"`
 	Stock                 uint32   `json:"stock"`
 	ReservedStock         uint32   `json:"reserved_stock"`
-	Price                 float64  `json:"price"`
+	Price                 Decimal  `json:"price"`
 	VariationSKU          string   `json:"variation_sku"`
 	Status                string   `json:"status"`
 	CreateTime            uint32   `json:"create_time"`
 	UpdateTime            uint32   `json:"update_time"`
-	OriginalPrice         float64  `json:"original_price"`
-	InflatedOriginalPrice float64  `json:"inflated_original_price"`
-	InflatedPrice         float64  `json:"inflated_price"`
+	OriginalPrice         Decimal  `json:"original_price"`
+	InflatedOriginalPrice Decimal  `json:"inflated_original_price"`
+	InflatedPrice         Decimal  `json:"inflated_price"`
 	DiscountID            uint64   `json:"discount_id"`
 	ModifiedTime          uint32   `json:"modified_time"`
 	ItemID                uint64   `json:"item_id"`
 	TierIndex             []uint32 `json:"tier_index,omitempty"`
+
+	// Raw is only set with WithRawFields.
+	Raw *RawFields `json:"-"`
 }
 
 type VariationPriceRequest struct {
 	ItemID                uint64   `json:"item_id"`
 	VariationID                    uint64   `json:"variation_id"`
-	Price                 float64  `json:"price"`
-	ItemPrice float64 `json:"item_price"`
+	Price                 Decimal  `json:"price"`
+	ItemPrice Decimal `json:"item_price"`
 }
 
 /*
@@ -40,14 +43,14 @@ type VariationPriceResponseBatchResult struct {
 }
 
 type VariationPriceResponseBatchResultModification struct {
-	ItemID                uint64   `json:"item_id"`
-	VariationID                    uint64   `json:"variation_id"`
-	ItemPrice float64 `json:"item_price"`
+	ItemID                FlexInt   `json:"item_id"`
+	VariationID                    FlexInt   `json:"variation_id"`
+	ItemPrice Decimal `json:"item_price"`
 }
 
 type VariationPriceResponseBatchResultFailure struct {
-	ItemID                uint64   `json:"item_id"`
-	VariationID                    uint64   `json:"variation_id"`
+	ItemID                FlexInt   `json:"item_id"`
+	VariationID                    FlexInt   `json:"variation_id"`
 	ErrorDiscription string `json:"error_description"`
 
 }
@@ -73,7 +76,7 @@ type AddVariationsRequest struct {
 }
 
 type AddVariationsReponse struct {
-	ItemID       uint64      `json:"item_id"`
+	ItemID       FlexInt     `json:"item_id"`
 	ModifiedTime uint32      `json:"modified_time"`
 	Variations   []Variation `json:"variations"`
 	RequestID    string      `json:"request_id"`
@@ -105,10 +108,10 @@ type DeleteVariationRequest struct {
 }
 
 type DeleteVariationResponse struct {
-	ItemID       uint64 `json:"item_id"`
-	VariationID  uint64 `json:"variation_id"`
-	ModifiedTime uint32 `json:"modified_time"`
-	RequestID    string `json:"request_id"`
+	ItemID       FlexInt `json:"item_id"`
+	VariationID  FlexInt `json:"variation_id"`
+	ModifiedTime uint32  `json:"modified_time"`
+	RequestID    string  `json:"request_id"`
 }
 
 // Delete https://open.shopee.com/documents?module=2&type=1&id=371
@@ -128,7 +131,7 @@ func (s *VariationServiceOp) Delete(sid, itemID, variationID uint64) error {
 type UpdateVariationPriceRequest struct {
 	ItemID      uint64  `json:"item_id"`
 	VariationID uint64  `json:"variation_id"`
-	Price       float64 `json:"price"`
+	Price       Decimal `json:"price"`
 	ShopID      uint64  `json:"shopid"`
 }
 